@@ -0,0 +1,160 @@
+// Package analyzer provides a go/analysis checker for common knownerror misuse:
+//
+//   - assigning the result of a Proxy method back to a package-level sentinel,
+//     which mutates the sentinel for every later caller;
+//   - comparing a *knownerror.Proxy with == or != instead of errors.Is;
+//   - calling a deriving method on a provably nil Proxy, e.g. on the result of
+//     knownerror.Wrap(nil).
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const pkgPath = "github.com/pprishchepa/knownerror"
+
+// Analyzer reports misuse of knownerror sentinels.
+var Analyzer = &analysis.Analyzer{
+	Name:     "knownerror",
+	Doc:      "report misuse of knownerror sentinels",
+	URL:      "https://pkg.go.dev/github.com/pprishchepa/knownerror/analyzer",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.CallExpr)(nil),
+	}
+	insp.Preorder(nodeFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			checkAssign(pass, n)
+		case *ast.BinaryExpr:
+			checkCompare(pass, n)
+		case *ast.CallExpr:
+			checkNilReceiver(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// checkAssign reports `ErrX = ErrX.WithCause(err)` where ErrX is a package-level variable.
+func checkAssign(pass *analysis.Pass, stmt *ast.AssignStmt) {
+	if stmt.Tok != token.ASSIGN || len(stmt.Lhs) != len(stmt.Rhs) {
+		return
+	}
+	for i, lhs := range stmt.Lhs {
+		v := packageVar(pass, lhs)
+		if v == nil || !isProxy(v.Type()) {
+			continue
+		}
+		call, ok := ast.Unparen(stmt.Rhs[i]).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if name, ok := proxyMethod(pass, call); ok {
+			pass.Reportf(stmt.Pos(), "result of %s assigned to package-level sentinel %s; derive into a local variable instead", name, v.Name())
+		}
+	}
+}
+
+// checkCompare reports == and != comparisons involving a *knownerror.Proxy.
+func checkCompare(pass *analysis.Pass, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	x, y := pass.TypesInfo.Types[expr.X], pass.TypesInfo.Types[expr.Y]
+	if x.IsNil() || y.IsNil() {
+		return
+	}
+	if isProxy(x.Type) || isProxy(y.Type) {
+		pass.Reportf(expr.OpPos, "comparing *knownerror.Proxy with %s; use errors.Is", expr.Op)
+	}
+}
+
+// checkNilReceiver reports Proxy methods called on a receiver that is provably
+// nil: a nil conversion or knownerror.Wrap(nil). Such calls panic.
+func checkNilReceiver(pass *analysis.Pass, call *ast.CallExpr) {
+	name, ok := proxyMethod(pass, call)
+	if !ok {
+		return
+	}
+	recv := ast.Unparen(call.Fun.(*ast.SelectorExpr).X)
+	inner, ok := recv.(*ast.CallExpr)
+	if !ok || len(inner.Args) == 0 || !pass.TypesInfo.Types[inner.Args[0]].IsNil() {
+		return
+	}
+	switch {
+	case pass.TypesInfo.Types[inner.Fun].IsType() && len(inner.Args) == 1:
+		pass.Reportf(call.Pos(), "%s called on a nil Proxy", name)
+	case isFunc(pass, inner.Fun, "Wrap"), isFunc(pass, inner.Fun, "WrapSkip"):
+		pass.Reportf(call.Pos(), "%s called on the result of knownerror.Wrap(nil), which is nil", name)
+	}
+}
+
+// packageVar returns the package-level variable referenced by expr, if any.
+func packageVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
+
+// proxyMethod reports whether call invokes a method of *knownerror.Proxy returning a *Proxy.
+func proxyMethod(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	s := pass.TypesInfo.Selections[sel]
+	if s == nil || s.Kind() != types.MethodVal || !isProxy(s.Recv()) {
+		return "", false
+	}
+	sig := s.Type().(*types.Signature)
+	if sig.Results().Len() != 1 || !isProxy(sig.Results().At(0).Type()) {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+func isFunc(pass *analysis.Pass, expr ast.Expr, name string) bool {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Name() == name && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath
+}
+
+func isProxy(t types.Type) bool {
+	ptr, ok := types.Unalias(t).(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "Proxy" && obj.Pkg() != nil && obj.Pkg().Path() == pkgPath
+}
@@ -0,0 +1,13 @@
+// Command knownerrorvet runs the knownerror analyzer:
+//
+//	go vet -vettool=$(which knownerrorvet) ./...
+package main
+
+import (
+	"github.com/pprishchepa/knownerror/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,39 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/pprishchepa/knownerror"
+)
+
+var ErrNotFound = knownerror.New("not found")
+
+var ErrUserNotFound = knownerror.New("user not found").Extends(ErrNotFound)
+
+func assign(err error) error {
+	ErrUserNotFound = ErrUserNotFound.WithCause(err) // want `result of WithCause assigned to package-level sentinel ErrUserNotFound`
+	ErrNotFound = knownerror.New("not found")
+
+	local := ErrUserNotFound.WithCause(err)
+	local = local.Extends(ErrNotFound)
+	return local
+}
+
+func compare(err error) bool {
+	if err == ErrNotFound { // want `comparing \*knownerror.Proxy with ==; use errors.Is`
+		return true
+	}
+	if ErrUserNotFound != err { // want `comparing \*knownerror.Proxy with !=; use errors.Is`
+		return false
+	}
+	return ErrNotFound != nil && errors.Is(err, ErrNotFound)
+}
+
+func nilReceiver(err error) error {
+	_ = (*knownerror.Proxy)(nil).WithCause(err)    // want `WithCause called on a nil Proxy`
+	_ = (*knownerror.Proxy)(nil).WithOp("a.Op")    // want `WithOp called on a nil Proxy`
+	_ = knownerror.Wrap(nil).Extends(err)          // want `Extends called on the result of knownerror.Wrap\(nil\)`
+	_ = knownerror.WrapSkip(nil, 1).WithCause(err) // want `WithCause called on the result of knownerror.Wrap\(nil\)`
+	_ = knownerror.Wrap(err).WithCause(err)
+	return ErrNotFound.WithCause(err)
+}
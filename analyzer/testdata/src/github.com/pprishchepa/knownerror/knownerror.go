@@ -0,0 +1,17 @@
+package knownerror
+
+type Proxy struct{}
+
+func New(text string) *Proxy { return &Proxy{} }
+
+func Wrap(err error) *Proxy { return &Proxy{} }
+
+func WrapSkip(err error, skip int) *Proxy { return &Proxy{} }
+
+func (e *Proxy) WithCause(cause error) *Proxy { return e }
+
+func (e *Proxy) Extends(errs ...error) *Proxy { return e }
+
+func (e *Proxy) WithOp(op string) *Proxy { return e }
+
+func (e *Proxy) Error() string { return "" }
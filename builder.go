@@ -0,0 +1,89 @@
+package knownerror
+
+// Builder assembles a Proxy across several steps, e.g. collecting categories
+// and causes while processing, without allocating an intermediate Proxy per
+// step. A Builder is not safe for concurrent use:
+//
+//	b := knownerror.NewBuilder("import failed").Extend(ErrBadRequest)
+//	for _, row := range rows {
+//		if err := importRow(row); err != nil {
+//			b.Cause(err)
+//		}
+//	}
+//	return b.Build()
+type Builder struct {
+	text   string
+	cats   []error
+	causes []error
+	opts   []Option
+}
+
+// NewBuilder creates a Builder for an error with the given message.
+func NewBuilder(text string) *Builder {
+	return &Builder{text: text}
+}
+
+// Extend adds extended errors, like Extends. Nil errors are ignored.
+func (b *Builder) Extend(errs ...error) *Builder {
+	b.cats = append(b.cats, errs...)
+	return b
+}
+
+// Cause adds a root cause. With several causes the built Proxy behaves as if
+// created with WithCauses. Nil errors are ignored.
+func (b *Builder) Cause(err error) *Builder {
+	if err != nil {
+		b.causes = append(b.causes, err)
+	}
+	return b
+}
+
+// Op sets the operation, like WithOp.
+func (b *Builder) Op(op string) *Builder {
+	return b.With(Op(op))
+}
+
+// Hint sets the remediation hint, like WithHint.
+func (b *Builder) Hint(hint string) *Builder {
+	return b.With(Hint(hint))
+}
+
+// PublicHint sets a hint that is safe to show to clients, like WithPublicHint.
+func (b *Builder) PublicHint(hint string) *Builder {
+	return b.With(PublicHint(hint))
+}
+
+// HelpURL sets the help link, like WithHelpURL.
+func (b *Builder) HelpURL(url string) *Builder {
+	return b.With(HelpURL(url))
+}
+
+// ExitCode sets the process exit code, like WithExitCode.
+func (b *Builder) ExitCode(code int) *Builder {
+	return b.With(ExitCode(code))
+}
+
+// With applies Compose options when the error is built.
+func (b *Builder) With(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Build creates the Proxy. The Builder can be reused; later changes do not
+// affect Proxies already built.
+func (b *Builder) Build() *Proxy {
+	p := &Proxy{}
+	Message(b.text)(p)
+	Categories(b.cats...)(p)
+	switch len(b.causes) {
+	case 0:
+	case 1:
+		p.cause = b.causes[0]
+	default:
+		p.cause = &multiCause{errs: append([]error(nil), b.causes...)}
+	}
+	for _, opt := range b.opts {
+		opt(p)
+	}
+	return create(p, 1)
+}
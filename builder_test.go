@@ -0,0 +1,46 @@
+package knownerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("some not found")
+	errExternal := errors.New("some external")
+	cause1 := errors.New("some first cause")
+	cause2 := errors.New("some second cause")
+
+	b := NewBuilder("some error").Extend(errNotFound).Op("some.Op")
+	b.Cause(cause1).Cause(nil).Cause(cause2)
+	b.Extend(nil, errExternal).Hint("some hint").HelpURL("https://example.com/some-help").ExitCode(3)
+	err := b.With(ID("some-id")).Build()
+
+	require.Equal(t, "some error", err.Error())
+	require.Equal(t, []error{errNotFound, errExternal}, ExtendsList(err))
+	require.Equal(t, []error{cause1, cause2}, err.Causes())
+	require.Equal(t, "some.Op", err.Op())
+	require.Equal(t, "some hint", err.Hint())
+	require.Equal(t, "https://example.com/some-help", err.HelpURL())
+	require.Equal(t, 3, err.ExitCode())
+	require.Equal(t, "some-id", err.ID())
+	require.True(t, errors.Is(err, errNotFound))
+	require.True(t, errors.Is(err, errExternal))
+}
+
+func TestBuilder__reuse(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	b := NewBuilder("some error")
+	first := b.Build()
+	second := b.Cause(cause).Build()
+
+	require.Nil(t, first.Cause())
+	require.Same(t, cause, second.Cause())
+	require.Empty(t, ExtendsList(second))
+}
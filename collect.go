@@ -0,0 +1,121 @@
+package knownerror
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Collector accumulates errors and combines them into a single Proxy. The zero
+// value is ready to use. A Collector is not safe for concurrent use:
+//
+//	var c knownerror.Collector
+//	for _, item := range items {
+//		c.Add(process(item))
+//	}
+//	return c.Err()
+type Collector struct {
+	errs []error
+}
+
+// Add records err. Nil errors are ignored.
+func (c *Collector) Add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// AddIf records err if cond is true.
+func (c *Collector) AddIf(cond bool, err error) {
+	if cond {
+		c.Add(err)
+	}
+}
+
+// Len returns the number of recorded errors.
+func (c *Collector) Len() int {
+	return len(c.errs)
+}
+
+// Errors returns a copy of the recorded errors in the order they were added.
+func (c *Collector) Errors() []error {
+	return slices.Clone(c.errs)
+}
+
+// Err returns nil if no errors were recorded. Otherwise it returns a Proxy that
+// matches every recorded error via errors.Is and whose message joins their
+// messages with "; ".
+func (c *Collector) Err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return combine(c.errs)
+}
+
+func combine(errs []error) *Proxy {
+	var b strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(messageOf(err))
+	}
+	return Compose(nil, Message(b.String()), Categories(errs...))
+}
+
+// Group is a Collector safe for concurrent use, with errgroup-style helpers for
+// fan-out workers. The zero value is ready to use:
+//
+//	var g knownerror.Group
+//	for _, host := range hosts {
+//		g.Go(func() error { return ping(host) })
+//	}
+//	err := g.Wait()
+//	g.Count(ErrTimeout) // how many workers timed out
+type Group struct {
+	wg sync.WaitGroup
+	mu sync.Mutex
+	c  Collector
+}
+
+// Add records err. Nil errors are ignored.
+func (g *Group) Add(err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.c.Add(err)
+}
+
+// Go calls fn in a new goroutine and records its error.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.Add(fn())
+	}()
+}
+
+// Wait blocks until all functions started with Go have returned, then returns
+// the combined error like Collector.Err.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.c.Err()
+}
+
+// Count returns how many recorded errors match category via errors.Is.
+func (g *Group) Count(category error) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := 0
+	for _, err := range g.c.errs {
+		if errors.Is(err, category) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package knownerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	err1 := New("some first error")
+	err2 := errors.New("some second error")
+	err3 := New("some third error")
+
+	var c Collector
+	c.Add(err1)
+	c.Add(nil)
+	c.AddIf(true, err2)
+	c.AddIf(false, err3)
+	err := c.Err()
+
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, []error{err1, err2}, c.Errors())
+	require.Equal(t, "some first error; some second error", err.Error())
+	require.True(t, errors.Is(err, err1))
+	require.True(t, errors.Is(err, err2))
+	require.False(t, errors.Is(err, err3))
+	require.True(t, IsKnown(err))
+}
+
+func TestCollector__cause_in_message(t *testing.T) {
+	t.Parallel()
+
+	var c Collector
+	c.Add(New("some first error").WithCause(errors.New("some cause")).WithCauseInMessage(true))
+	c.Add(errors.New("some second error"))
+
+	require.Equal(t, "some first error; some second error", c.Err().Error())
+}
+
+func TestCollector_Errors__copy(t *testing.T) {
+	t.Parallel()
+
+	err1 := errors.New("some first error")
+	var c Collector
+	c.Add(err1)
+	c.Errors()[0] = errors.New("some other error")
+
+	require.Equal(t, []error{err1}, c.Errors())
+}
+
+func TestCollector__empty(t *testing.T) {
+	t.Parallel()
+
+	var c Collector
+	c.Add(nil)
+	require.NoError(t, c.Err())
+	require.Zero(t, c.Len())
+}
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	errCategory := errors.New("some category")
+	errOther := New("some other error")
+
+	var g Group
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			switch {
+			case i%2 == 0:
+				return New("some error").Extends(errCategory)
+			case i == 5:
+				return errOther
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	require.True(t, errors.Is(err, errCategory))
+	require.True(t, errors.Is(err, errOther))
+	require.Equal(t, 5, g.Count(errCategory))
+	require.Equal(t, 1, g.Count(errOther))
+	require.Zero(t, g.Count(ErrTimeout))
+}
+
+func TestGroup__no_errors(t *testing.T) {
+	t.Parallel()
+
+	var g Group
+	g.Go(func() error { return nil })
+	require.NoError(t, g.Wait())
+}
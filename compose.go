@@ -0,0 +1,157 @@
+package knownerror
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Option sets a component of a Proxy built by Compose.
+type Option func(*Proxy)
+
+// Compose builds a Proxy around base from already-known components in one go,
+// instead of chaining With* calls that each allocate a copy:
+//
+//	err := knownerror.Compose(nil,
+//		knownerror.Message("user not found"),
+//		knownerror.Categories(ErrNotFound),
+//		knownerror.CausedBy(sql.ErrNoRows),
+//	)
+//
+// Unlike the With* methods, Compose does not derive from an existing Proxy:
+// pass it as base to keep it reachable via errors.Unwrap and errors.Is.
+func Compose(base error, opts ...Option) *Proxy {
+	p := &Proxy{base: base}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return create(p, 1)
+}
+
+// Message sets the base error to errors.New(text).
+func Message(text string) Option {
+	return func(p *Proxy) {
+		p.base = errors.New(text)
+		if p.meta != nil {
+			m := p.setMeta()
+			m.format, m.inline = "", ""
+		}
+	}
+}
+
+// CausedBy sets the cause.
+func CausedBy(cause error) Option {
+	return func(p *Proxy) {
+		p.setCause(cause)
+	}
+}
+
+// Categories adds extended errors, like Extends. Nil errors are ignored.
+func Categories(errs ...error) Option {
+	return func(p *Proxy) {
+		if nonNilErrs := nonNil(errs); len(nonNilErrs) > 0 {
+			p.extends = &chain{prev: p.extends, errs: nonNilErrs, size: p.extends.len() + len(nonNilErrs)}
+			p.restore(nonNilErrs)
+		}
+	}
+}
+
+// Op sets the operation, like WithOp.
+func Op(op string) Option {
+	return func(p *Proxy) {
+		p.op = op
+	}
+}
+
+// ID sets the instance ID, like WithID.
+func ID(id string) Option {
+	return func(p *Proxy) {
+		p.setMeta().id = id
+	}
+}
+
+// HelpURL sets the help link, like WithHelpURL.
+func HelpURL(url string) Option {
+	return func(p *Proxy) {
+		p.setMeta().helpURL = url
+	}
+}
+
+// Hint sets the remediation hint, like WithHint.
+func Hint(hint string) Option {
+	return func(p *Proxy) {
+		m := p.setMeta()
+		m.hint, m.public = hint, false
+	}
+}
+
+// PublicHint sets a hint that is safe to show to clients, like WithPublicHint.
+func PublicHint(hint string) Option {
+	return func(p *Proxy) {
+		m := p.setMeta()
+		m.hint, m.public = hint, hint != ""
+	}
+}
+
+// Tags adds tags, like WithTags.
+func Tags(tags ...string) Option {
+	return func(p *Proxy) {
+		p.setMeta().tags = appendTags(slices.Clip(p.md().tags), tags)
+	}
+}
+
+// Domain sets the owning subsystem, like WithDomain.
+func Domain(domain string) Option {
+	return func(p *Proxy) {
+		p.setMeta().domain = domain
+	}
+}
+
+// RetryAfter sets the retry delay, like WithRetryAfter.
+func RetryAfter(d time.Duration) Option {
+	return func(p *Proxy) {
+		p.setMeta().retry = d
+	}
+}
+
+// ExitCode sets the process exit code, like WithExitCode.
+func ExitCode(code int) Option {
+	return func(p *Proxy) {
+		p.setMeta().exit = code
+	}
+}
+
+// Timestamp sets the time reported by Timestamp.
+func Timestamp(t time.Time) Option {
+	return func(p *Proxy) {
+		p.setMeta().created = t
+	}
+}
+
+// TransparentCause makes the cause visible to errors.Is and errors.As, like WithTransparentCause.
+func TransparentCause() Option {
+	return func(p *Proxy) {
+		p.transparentCause = true
+	}
+}
+
+// CauseInMessage sets whether Error includes the cause message, like WithCauseInMessage.
+func CauseInMessage(enabled bool) Option {
+	return func(p *Proxy) {
+		p.causeMessage = flagOf(enabled)
+	}
+}
+
+// Timeout sets the value reported by Timeout, like WithTimeout.
+func Timeout(timeout bool) Option {
+	return func(p *Proxy) {
+		p.timeout = flagOf(timeout)
+	}
+}
+
+// Temporary sets the value reported by Temporary, like WithTemporary.
+func Temporary(temporary bool) Option {
+	return func(p *Proxy) {
+		p.temporary = flagOf(temporary)
+	}
+}
@@ -0,0 +1,73 @@
+package knownerror
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	category1 := errors.New("some first category")
+	category2 := errors.New("some second category")
+	cause := errors.New("some cause")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := Compose(nil,
+		Message("some error"),
+		Categories(category1, nil),
+		Categories(category2),
+		CausedBy(cause),
+		Op("some.Op"),
+		ID("some-id"),
+		HelpURL("https://example.com/some-help"),
+		Hint("some hint"),
+		Tags("some-tag", "some-other-tag"),
+		Tags("some-tag"),
+		Domain("some-domain"),
+		RetryAfter(time.Minute),
+		ExitCode(3),
+		Timestamp(created),
+		Timeout(true),
+		Temporary(true),
+	)
+
+	require.Equal(t, "some error", err.Error())
+	require.Equal(t, []error{category1, category2}, ExtendsList(err))
+	require.Same(t, cause, err.Cause())
+	require.Equal(t, "some.Op", err.Op())
+	require.Equal(t, "some-id", err.ID())
+	require.Equal(t, "https://example.com/some-help", err.HelpURL())
+	require.Equal(t, "some hint", err.Hint())
+	require.Equal(t, []string{"some-tag", "some-other-tag"}, err.Tags())
+	require.Equal(t, "some-domain", err.Domain())
+	require.Equal(t, time.Minute, err.RetryAfter())
+	require.Equal(t, 3, ExitCodeOf(err))
+	require.Equal(t, created, err.Timestamp())
+	require.True(t, IsTimeout(err))
+	require.True(t, isTemporary(err))
+	require.False(t, errors.Is(err, cause))
+}
+
+func TestCompose__base(t *testing.T) {
+	t.Parallel()
+
+	base := New("some base error")
+	cause := errors.New("some cause")
+	err := Compose(base, CausedBy(cause), TransparentCause())
+
+	require.Equal(t, "some base error", err.Error())
+	require.True(t, errors.Is(err, base))
+	require.True(t, errors.Is(err, cause))
+}
+
+func TestCompose__empty(t *testing.T) {
+	t.Parallel()
+
+	err := Compose(nil)
+	require.NotNil(t, err)
+	require.Empty(t, err.Error())
+}
@@ -0,0 +1,252 @@
+package knownerror
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"sync"
+	"time"
+)
+
+const binaryVersion = 2
+
+var errInvalidBinary = errors.New("knownerror: invalid binary encoding")
+
+func init() {
+	// Let gob encode a *Proxy held in an error interface value.
+	gob.Register(&Proxy{})
+	RegisterSentinel(ErrTimeout, ErrCanceled, ErrInvalid, ErrResourceExhausted,
+		context.DeadlineExceeded, context.Canceled)
+}
+
+var sentinels struct {
+	mu        sync.RWMutex
+	byMessage map[string]error
+}
+
+// RegisterSentinel lets UnmarshalBinary and GobDecode restore categories with
+// the message of a registered error as that error, so the decoded Proxy still
+// matches it via errors.Is and errors.As:
+//
+//	var ErrUserNotFound = knownerror.New("user not found").Extends(knownerror.ErrNotFound)
+//
+//	func init() {
+//		knownerror.RegisterSentinel(ErrUserNotFound)
+//	}
+//
+// The HTTP status errors, ErrTimeout, ErrCanceled, ErrInvalid,
+// ErrResourceExhausted, context.DeadlineExceeded and context.Canceled are
+// registered already. A later registration with the same message replaces an
+// earlier one. Register sentinels during program initialization.
+func RegisterSentinel(errs ...error) {
+	sentinels.mu.Lock()
+	defer sentinels.mu.Unlock()
+	if sentinels.byMessage == nil {
+		sentinels.byMessage = make(map[string]error)
+	}
+	for _, err := range nonNil(errs) {
+		sentinels.byMessage[messageOf(err)] = err
+	}
+}
+
+// sentinel returns the registered error with the message msg, or a plain
+// error with that message.
+func sentinel(msg string) error {
+	sentinels.mu.RLock()
+	defer sentinels.mu.RUnlock()
+	if err, ok := sentinels.byMessage[msg]; ok {
+		return err
+	}
+	return errors.New(msg)
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form is the error message.
+func (e *Proxy) MarshalText() ([]byte, error) {
+	return []byte(e.Error()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It replaces e with a Proxy
+// holding the message. Unmarshal into a fresh Proxy, never into a sentinel.
+func (e *Proxy) UnmarshalText(text []byte) error {
+	*e = Proxy{base: errors.New(string(text))}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding carries the
+// message, cause message, op, ID, hint, help URL, domain, tags, retry delay,
+// exit code, timestamp and flags, and the messages of the extended errors.
+//
+// Identities only exist in-process, so the decoded Proxy loses:
+//   - the identity of extended errors not registered via RegisterSentinel,
+//     restored as plain errors with the same messages; their own categories
+//     are dropped;
+//   - the cause chain, restored as a single plain error with the cause message,
+//     so several causes become one;
+//   - the base error and the errors it was derived from: the decoded Proxy does
+//     not match the original via errors.Is;
+//   - the quota details and the origin frame.
+func (e *Proxy) MarshalBinary() ([]byte, error) {
+	var cause string
+	if e.cause != nil {
+		cause = Sanitize(e.cause.Error())
+	}
+	m := e.md()
+	var created int64
+	if !m.created.IsZero() {
+		created = m.created.UnixNano()
+	}
+	flags := uint64(e.timeout) | uint64(e.temporary)<<2 | uint64(e.causeMessage)<<5
+	if e.transparentCause {
+		flags |= 1 << 4
+	}
+	if m.public {
+		flags |= 1 << 7
+	}
+	if e.implicitCause {
+		flags |= 1 << 8
+	}
+
+	b := []byte{binaryVersion}
+	for _, s := range [...]string{e.Message(), cause, Sanitize(m.inline), e.op, m.id, m.hint, m.helpURL, m.domain} {
+		b = appendString(b, s)
+	}
+	exts := e.extendsList()
+	b = binary.AppendUvarint(b, uint64(len(exts)))
+	for _, ext := range exts {
+		b = appendString(b, Sanitize(messageOf(ext)))
+	}
+	b = binary.AppendUvarint(b, uint64(len(m.tags)))
+	for _, tag := range m.tags {
+		b = appendString(b, tag)
+	}
+	b = binary.AppendVarint(b, created)
+	b = binary.AppendVarint(b, int64(m.retry))
+	b = binary.AppendVarint(b, int64(m.exit))
+	return binary.AppendUvarint(b, flags), nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces e with the
+// decoded Proxy; see MarshalBinary for what the encoding loses. Unmarshal into
+// a fresh Proxy, never into a sentinel.
+func (e *Proxy) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errInvalidBinary
+	}
+	d := decoder{data: data[1:]}
+
+	var strs [8]string
+	for i := range strs {
+		strs[i] = d.string()
+	}
+	exts := make([]error, d.count())
+	for i := range exts {
+		exts[i] = sentinel(d.string())
+	}
+	tags := make([]string, d.count())
+	for i := range tags {
+		tags[i] = d.string()
+	}
+	created := d.varint()
+	retry := d.varint()
+	exit := d.varint()
+	flags := d.uvarint()
+	if d.invalid || len(d.data) != 0 {
+		return errInvalidBinary
+	}
+
+	m := &meta{
+		inline:  strs[2],
+		id:      strs[4],
+		hint:    strs[5],
+		public:  flags&(1<<7) != 0,
+		helpURL: strs[6],
+		domain:  strs[7],
+		retry:   time.Duration(retry),
+		exit:    int(exit),
+	}
+	if len(tags) > 0 {
+		m.tags = tags
+	}
+	if created != 0 {
+		m.created = time.Unix(0, created)
+	}
+	p := Proxy{
+		base:             errors.New(strs[0]),
+		op:               strs[3],
+		meta:             m,
+		transparentCause: flags&(1<<4) != 0,
+		implicitCause:    flags&(1<<8) != 0,
+		timeout:          flag(flags & 3),
+		temporary:        flag(flags >> 2 & 3),
+		causeMessage:     flag(flags >> 5 & 3),
+	}
+	if strs[1] != "" {
+		p.cause = errors.New(strs[1])
+	}
+	if len(exts) > 0 {
+		p.extends = &chain{errs: exts, size: len(exts)}
+	}
+	*e = p
+	return nil
+}
+
+// decoder reads the binary encoding. Reading past the end or a malformed
+// value marks it invalid and yields zero values.
+type decoder struct {
+	data    []byte
+	invalid bool
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, size := binary.Uvarint(d.data)
+	if size <= 0 {
+		d.invalid = true
+		return 0
+	}
+	d.data = d.data[size:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, size := binary.Varint(d.data)
+	if size <= 0 {
+		d.invalid = true
+		return 0
+	}
+	d.data = d.data[size:]
+	return v
+}
+
+// count reads a length that must not exceed the remaining bytes, since every
+// element takes at least one byte.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.invalid = true
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) string() string {
+	n := d.count()
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+// GobEncode implements gob.GobEncoder using the binary encoding.
+func (e *Proxy) GobEncode() ([]byte, error) {
+	return e.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary encoding.
+func (e *Proxy) GobDecode(data []byte) error {
+	return e.UnmarshalBinary(data)
+}
@@ -0,0 +1,176 @@
+package knownerror
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.TextMarshaler     = (*Proxy)(nil)
+	_ encoding.TextUnmarshaler   = (*Proxy)(nil)
+	_ encoding.BinaryMarshaler   = (*Proxy)(nil)
+	_ encoding.BinaryUnmarshaler = (*Proxy)(nil)
+)
+
+func TestProxy_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	text, err := New("some error").WithCause(errors.New("some cause")).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "some error", string(text))
+
+	var p Proxy
+	require.NoError(t, p.UnmarshalText(text))
+	require.Equal(t, "some error", p.Error())
+	require.Nil(t, p.Cause())
+}
+
+func TestProxy_MarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	orig := Compose(nil,
+		Message("some error"),
+		CausedBy(errors.New("some cause")),
+		Op("some.Op"),
+		ID("some-id"),
+		PublicHint("some hint"),
+		HelpURL("https://example.com/some-help"),
+		Categories(ErrNotFound, errors.New("some category")),
+		Tags("some-tag", "some-other-tag"),
+		Domain("some-domain"),
+		RetryAfter(30*time.Second),
+		ExitCode(3),
+		Timestamp(created),
+		TransparentCause(),
+		Timeout(true),
+		Temporary(false),
+		CauseInMessage(true),
+	)
+
+	data, err := orig.MarshalBinary()
+	require.NoError(t, err)
+
+	var p Proxy
+	require.NoError(t, p.UnmarshalBinary(data))
+	require.Equal(t, "some error: some cause", p.Error())
+	require.Equal(t, "some cause", p.Cause().Error())
+	require.Equal(t, "some.Op", p.Op())
+	require.Equal(t, "some-id", p.ID())
+	require.Equal(t, "some hint", p.Hint())
+	require.Equal(t, "some hint", p.PublicHint())
+	require.Equal(t, "https://example.com/some-help", p.HelpURL())
+	require.Equal(t, []string{"not found", "some category"}, categoryMessages(&p))
+	require.True(t, errors.Is(&p, ErrNotFound))
+	require.Equal(t, http.StatusNotFound, StatusCode(&p))
+	require.Equal(t, []string{"some-tag", "some-other-tag"}, p.Tags())
+	require.Equal(t, "some-domain", p.Domain())
+	require.Equal(t, 30*time.Second, p.RetryAfter())
+	require.Equal(t, 3, p.ExitCode())
+	require.True(t, created.Equal(p.Timestamp()))
+	require.True(t, p.transparentCause)
+	require.True(t, IsTimeout(&p))
+	require.Equal(t, flagFalse, p.temporary)
+}
+
+func TestProxy_MarshalBinary__newf(t *testing.T) {
+	t.Parallel()
+
+	data, err := Newf("some context: %w (%d)", errors.New("some cause"), 42).MarshalBinary()
+	require.NoError(t, err)
+
+	var p Proxy
+	require.NoError(t, p.UnmarshalBinary(data))
+	require.Equal(t, "some context: some cause (42)", p.Error())
+	require.Equal(t, "some context (42)", p.Message())
+	require.Equal(t, "some cause", p.Cause().Error())
+	require.True(t, errors.Is(&p, p.Cause()))
+	require.False(t, p.WithCause(errors.New("some other cause")).causeVisible())
+}
+
+func categoryMessages(p *Proxy) []string {
+	var msgs []string
+	for _, ext := range ExtendsList(p) {
+		msgs = append(msgs, ext.Error())
+	}
+	return msgs
+}
+
+func TestProxy_MarshalBinary__minimal(t *testing.T) {
+	t.Parallel()
+
+	data, err := New("some error").MarshalBinary()
+	require.NoError(t, err)
+
+	var p Proxy
+	require.NoError(t, p.UnmarshalBinary(data))
+	require.Equal(t, "some error", p.Error())
+	require.Nil(t, p.Cause())
+	require.True(t, p.Timestamp().IsZero())
+	require.Equal(t, flagUnset, p.timeout)
+}
+
+func TestProxy_UnmarshalBinary__invalid(t *testing.T) {
+	t.Parallel()
+
+	data, err := New("some error").MarshalBinary()
+	require.NoError(t, err)
+
+	for _, bad := range [][]byte{nil, {0}, data[:3], append(data, 0)} {
+		var p Proxy
+		require.Error(t, p.UnmarshalBinary(bad))
+	}
+}
+
+func TestProxy_GobEncode(t *testing.T) {
+	t.Parallel()
+
+	type envelope struct {
+		Err error
+	}
+	in := envelope{Err: New("some error").WithCause(errors.New("some cause")).WithID("some-id")}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out envelope
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+
+	p, ok := AsProxy(out.Err)
+	require.True(t, ok)
+	require.Equal(t, "some error", p.Error())
+	require.Equal(t, "some cause", p.Cause().Error())
+	require.Equal(t, "some-id", p.ID())
+}
+
+func TestProxy_GobEncode__sentinels(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some registered sentinel").Extends(ErrConflict)
+	category := errors.New("some unregistered category")
+	RegisterSentinel(sentinel)
+
+	type envelope struct {
+		Err error
+	}
+	in := envelope{Err: New("some error").Extends(ErrNotFound, sentinel, category)}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out envelope
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+
+	require.True(t, errors.Is(out.Err, ErrNotFound))
+	require.Equal(t, http.StatusNotFound, StatusCode(out.Err))
+	require.True(t, errors.Is(out.Err, sentinel))
+	require.True(t, errors.Is(out.Err, ErrConflict))
+	require.False(t, errors.Is(out.Err, category))
+}
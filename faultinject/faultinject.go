@@ -0,0 +1,106 @@
+// Package faultinject makes call sites return known errors on demand, so that
+// error-handling paths can be tested end to end:
+//
+//	func (r *Repo) Get(ctx context.Context, id string) (*User, error) {
+//		if err := faultinject.Inject(ctx, "userrepo.Get"); err != nil {
+//			return nil, err
+//		}
+//		...
+//	}
+//
+//	// In a test:
+//	inj := faultinject.New()
+//	inj.Set("userrepo.Get", faultinject.Rule{Err: knownerror.ErrServiceUnavailable})
+//	ctx := faultinject.WithInjector(context.Background(), inj)
+package faultinject
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+)
+
+// Rule describes the fault injected at a point.
+type Rule struct {
+	// Err is the error returned by Inject.
+	Err error
+	// Probability is the chance, from 0 to 1, that Inject returns Err. Zero
+	// means always.
+	Probability float64
+	// Times limits how often the rule fires. Zero means unlimited.
+	Times int
+}
+
+// Injector holds the rules for injection points. The zero value is not
+// usable; create one with New. An Injector is safe for concurrent use.
+type Injector struct {
+	mu     sync.Mutex
+	rules  map[string]*Rule
+	random func() float64
+}
+
+// New creates an Injector without rules.
+func New() *Injector {
+	return &Injector{rules: make(map[string]*Rule), random: rand.Float64}
+}
+
+// Default is the Injector used by Inject when the context carries none. It is
+// empty unless a staging build or test registers rules on it.
+var Default = New()
+
+// Set replaces the rule for point. A rule without an error removes it.
+func (i *Injector) Set(point string, r Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if r.Err == nil {
+		delete(i.rules, point)
+		return
+	}
+	i.rules[point] = &r
+}
+
+// Reset removes all rules.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	clear(i.rules)
+}
+
+// Inject returns the error of the rule for point if it fires, or nil.
+func (i *Injector) Inject(point string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	r := i.rules[point]
+	if r == nil {
+		return nil
+	}
+	if r.Probability > 0 && i.random() >= r.Probability {
+		return nil
+	}
+	if r.Times > 0 {
+		r.Times--
+		if r.Times == 0 {
+			delete(i.rules, point)
+		}
+	}
+	return r.Err
+}
+
+type ctxKey struct{}
+
+// WithInjector returns a context whose Inject calls use i, so parallel tests
+// do not share rules.
+func WithInjector(ctx context.Context, i *Injector) context.Context {
+	return context.WithValue(ctx, ctxKey{}, i)
+}
+
+// Inject returns the error injected at point by the Injector carried by ctx,
+// or by Default if there is none.
+func Inject(ctx context.Context, point string) error {
+	if ctx != nil {
+		if i, ok := ctx.Value(ctxKey{}).(*Injector); ok && i != nil {
+			return i.Inject(point)
+		}
+	}
+	return Default.Inject(point)
+}
@@ -0,0 +1,65 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pprishchepa/knownerror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	inj := New()
+	inj.Set("some.Point", Rule{Err: knownerror.ErrServiceUnavailable})
+	ctx := WithInjector(context.Background(), inj)
+
+	err := Inject(ctx, "some.Point")
+	require.True(t, errors.Is(err, knownerror.ErrServiceUnavailable))
+	require.NoError(t, Inject(ctx, "some.OtherPoint"))
+	require.NoError(t, Inject(context.Background(), "some.Point"))
+}
+
+func TestInjector_Inject__times(t *testing.T) {
+	t.Parallel()
+
+	inj := New()
+	inj.Set("some.Point", Rule{Err: knownerror.ErrTimeout, Times: 2})
+
+	require.Error(t, inj.Inject("some.Point"))
+	require.Error(t, inj.Inject("some.Point"))
+	require.NoError(t, inj.Inject("some.Point"))
+}
+
+func TestInjector_Inject__probability(t *testing.T) {
+	t.Parallel()
+
+	inj := New()
+	rolls := []float64{0.1, 0.5, 0.29}
+	inj.random = func() float64 {
+		r := rolls[0]
+		rolls = rolls[1:]
+		return r
+	}
+	inj.Set("some.Point", Rule{Err: knownerror.ErrTimeout, Probability: 0.3})
+
+	require.Error(t, inj.Inject("some.Point"))
+	require.NoError(t, inj.Inject("some.Point"))
+	require.Error(t, inj.Inject("some.Point"))
+}
+
+func TestInjector_Set__remove(t *testing.T) {
+	t.Parallel()
+
+	inj := New()
+	inj.Set("some.Point", Rule{Err: knownerror.ErrTimeout})
+	inj.Set("some.OtherPoint", Rule{Err: knownerror.ErrTimeout})
+	inj.Set("some.Point", Rule{})
+	require.NoError(t, inj.Inject("some.Point"))
+	require.Error(t, inj.Inject("some.OtherPoint"))
+
+	inj.Reset()
+	require.NoError(t, inj.Inject("some.OtherPoint"))
+}
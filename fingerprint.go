@@ -0,0 +1,63 @@
+package knownerror
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// Fingerprint returns a stable hash of the outermost Proxy in err's chain,
+// built from its message template and its categories, including those of
+// wrapped Proxies. Errors created with Newf share a fingerprint regardless of
+// their arguments, so log pipelines and alerting can group identical failures:
+//
+//	a := ErrNoUser.WithCause(knownerror.Newf("user %d not found", 42))
+//	b := ErrNoUser.WithCause(knownerror.Newf("user %d not found", 7))
+//	knownerror.Fingerprint(a) == knownerror.Fingerprint(b) // true
+//
+// Causes, operations and other metadata do not affect the fingerprint. For
+// errors without a Proxy, the message is hashed. Returns "" if err is nil.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := fnv.New64a()
+	p, ok := AsProxy(err)
+	if !ok {
+		_, _ = h.Write([]byte(err.Error()))
+		return strconv.FormatUint(h.Sum64(), 16)
+	}
+	var cats []string
+	for q := p; q != nil; q, _ = q.base.(*Proxy) {
+		for _, ext := range q.extendsList() {
+			cats = append(cats, templateOf(ext))
+		}
+	}
+	slices.Sort(cats)
+	cats = slices.Compact(cats)
+
+	_, _ = h.Write([]byte(p.template()))
+	for _, cat := range cats {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(cat))
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// template returns the format passed to Newf, or the message otherwise.
+func (e *Proxy) template() string {
+	if e.md().format != "" {
+		return e.md().format
+	}
+	if base, ok := e.base.(*Proxy); ok && base != nil {
+		return base.template()
+	}
+	return e.Message()
+}
+
+func templateOf(err error) string {
+	if p, ok := err.(*Proxy); ok && p != nil {
+		return p.template()
+	}
+	return err.Error()
+}
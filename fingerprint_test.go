@@ -0,0 +1,53 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("some not found")
+	errExternal := errors.New("some external")
+
+	a := Newf("user %d not found", 42).Extends(errNotFound, errExternal)
+	b := Newf("user %d not found", 7).Extends(errExternal, errNotFound).WithCause(errors.New("some cause")).WithOp("some.Op")
+
+	require.NotEmpty(t, Fingerprint(a))
+	require.Equal(t, Fingerprint(a), Fingerprint(b))
+	require.Equal(t, Fingerprint(a), Fingerprint(fmt.Errorf("some context: %w", Wrap(a))))
+}
+
+func TestFingerprint__differs(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("some not found")
+	err := New("some error").Extends(errNotFound)
+
+	require.NotEqual(t, Fingerprint(err), Fingerprint(New("some other error").Extends(errNotFound)))
+	require.NotEqual(t, Fingerprint(err), Fingerprint(New("some error")))
+	require.NotEqual(t, Fingerprint(err), Fingerprint(err.Extends(errors.New("some category"))))
+}
+
+func TestFingerprint__cause_in_message(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	a := sentinel.WithCause(errors.New("some cause")).WithCauseInMessage(true)
+	b := Wrap(sentinel.WithCause(errors.New("some other cause")).WithCauseInMessage(true))
+
+	require.Equal(t, Fingerprint(sentinel), Fingerprint(a))
+	require.Equal(t, Fingerprint(sentinel), Fingerprint(b))
+}
+
+func TestFingerprint__unknown(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Fingerprint(errors.New("some error")), Fingerprint(errors.New("some error")))
+	require.NotEqual(t, Fingerprint(errors.New("some error")), Fingerprint(errors.New("some other error")))
+	require.Empty(t, Fingerprint(nil))
+}
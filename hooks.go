@@ -0,0 +1,74 @@
+package knownerror
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Hook observes a Proxy as it is created or derived. site is the call site of
+// the constructor or the deriving method.
+type Hook func(p *Proxy, site runtime.Frame)
+
+var hooks struct {
+	mu     sync.RWMutex
+	create []Hook
+	wrap   []Hook
+	// active is set once any hook is registered, so that call sites are only
+	// resolved when someone is listening.
+	active atomic.Bool
+}
+
+// OnCreate registers fn to be called for every Proxy created by New, Newf,
+// Wrap, Compose and Builder.Build, enabling metrics or auditing without
+// changing call sites:
+//
+//	knownerror.OnCreate(func(p *knownerror.Proxy, site runtime.Frame) {
+//		errorsCreated.WithLabelValues(site.Function).Inc()
+//	})
+//
+// Sentinels declared at package level are created before hooks are registered
+// and are not reported. Register hooks during program initialization.
+func OnCreate(fn Hook) {
+	register(&hooks.create, fn)
+}
+
+// OnWrap registers fn to be called for every Proxy derived via WithCause,
+// WithCauses, Extends, or Clone with a new cause or categories. fn receives the
+// derived Proxy.
+func OnWrap(fn Hook) {
+	register(&hooks.wrap, fn)
+}
+
+func register(list *[]Hook, fn Hook) {
+	if fn == nil {
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	*list = append(*list, fn)
+	hooks.active.Store(true)
+}
+
+// runHooks calls the hooks in list with p and the call site skip frames above
+// the caller of runHooks. Returns p for convenience.
+func runHooks(list *[]Hook, p *Proxy, skip int) *Proxy {
+	if !hooks.active.Load() {
+		return p
+	}
+	hooks.mu.RLock()
+	fns := *list
+	hooks.mu.RUnlock()
+	if len(fns) == 0 {
+		return p
+	}
+	var pcs [1]uintptr
+	var site runtime.Frame
+	if runtime.Callers(skip+2, pcs[:]) > 0 {
+		site, _ = runtime.CallersFrames(pcs[:]).Next()
+	}
+	for _, fn := range fns {
+		fn(p, site)
+	}
+	return p
+}
@@ -0,0 +1,104 @@
+package knownerror
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnCreate(t *testing.T) {
+	t.Cleanup(resetHooks)
+
+	var created []*Proxy
+	var sites []runtime.Frame
+	OnCreate(func(p *Proxy, site runtime.Frame) {
+		created = append(created, p)
+		sites = append(sites, site)
+	})
+
+	err1 := New("some error")
+	err2 := Newf("some %s error", "formatted")
+	err3 := Wrap(errors.New("some wrapped error"))
+	err4 := Compose(nil, Message("some composed error"))
+
+	require.Equal(t, []*Proxy{err1, err2, err3, err4}, created)
+	for _, site := range sites {
+		require.Equal(t, "github.com/pprishchepa/knownerror.TestOnCreate", site.Function)
+		require.Contains(t, site.File, "hooks_test.go")
+	}
+}
+
+func TestOnCreate__from_status_code(t *testing.T) {
+	t.Cleanup(resetHooks)
+
+	var status int
+	var site runtime.Frame
+	OnCreate(func(p *Proxy, s runtime.Frame) {
+		status = StatusCode(p)
+		site = s
+	})
+
+	_ = FromStatusCode(499)
+
+	require.Equal(t, 499, status)
+	require.Equal(t, "github.com/pprishchepa/knownerror.TestOnCreate__from_status_code", site.Function)
+}
+
+func TestOnWrap(t *testing.T) {
+	t.Cleanup(resetHooks)
+
+	sentinel := New("some error")
+
+	var wrapped []*Proxy
+	var site runtime.Frame
+	OnWrap(func(p *Proxy, s runtime.Frame) {
+		wrapped = append(wrapped, p)
+		site = s
+	})
+
+	err1 := sentinel.WithCause(errors.New("some cause"))
+	err2 := sentinel.Extends(errors.New("some category"))
+	err3 := sentinel.Clone(CausedBy(errors.New("some cause")))
+	err4 := sentinel.Clone(Categories(errors.New("some category")))
+	_ = sentinel.WithCause(nil)
+	_ = sentinel.WithOp("some.Op")
+	_ = err1.Clone(Op("some.Op"))
+
+	require.Equal(t, []*Proxy{err1, err2, err3, err4}, wrapped)
+	require.Equal(t, "github.com/pprishchepa/knownerror.TestOnWrap", site.Function)
+}
+
+func TestOnCreate__skip(t *testing.T) {
+	t.Cleanup(resetHooks)
+
+	var sites []runtime.Frame
+	OnCreate(func(_ *Proxy, site runtime.Frame) {
+		sites = append(sites, site)
+	})
+
+	_ = wrapHelper(errors.New("some error"))
+	_ = newHelper("some error")
+	_ = WrapSkip(nil, 1)
+
+	require.Len(t, sites, 2)
+	for _, site := range sites {
+		require.Equal(t, "github.com/pprishchepa/knownerror.TestOnCreate__skip", site.Function)
+	}
+}
+
+func wrapHelper(err error) *Proxy {
+	return WrapSkip(err, 1)
+}
+
+func newHelper(text string) *Proxy {
+	return NewSkip(text, 1)
+}
+
+func resetHooks() {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.create, hooks.wrap = nil, nil
+	hooks.active.Store(false)
+}
@@ -0,0 +1,129 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Known errors for HTTP 4xx and 5xx statuses. Use them as categories so that
+// errors map to a status with StatusCode, and FromStatusCode to translate
+// upstream statuses:
+//
+//	var ErrUserNotFound = knownerror.New("user not found").Extends(knownerror.ErrNotFound)
+//	knownerror.StatusCode(ErrUserNotFound) // 404
+var (
+	ErrBadRequest                   = statusError(http.StatusBadRequest)
+	ErrUnauthorized                 = statusError(http.StatusUnauthorized)
+	ErrPaymentRequired              = statusError(http.StatusPaymentRequired)
+	ErrForbidden                    = statusError(http.StatusForbidden)
+	ErrNotFound                     = statusError(http.StatusNotFound)
+	ErrMethodNotAllowed             = statusError(http.StatusMethodNotAllowed)
+	ErrNotAcceptable                = statusError(http.StatusNotAcceptable)
+	ErrProxyAuthRequired            = statusError(http.StatusProxyAuthRequired)
+	ErrRequestTimeout               = statusError(http.StatusRequestTimeout)
+	ErrConflict                     = statusError(http.StatusConflict)
+	ErrGone                         = statusError(http.StatusGone)
+	ErrLengthRequired               = statusError(http.StatusLengthRequired)
+	ErrPreconditionFailed           = statusError(http.StatusPreconditionFailed)
+	ErrRequestEntityTooLarge        = statusError(http.StatusRequestEntityTooLarge)
+	ErrRequestURITooLong            = statusError(http.StatusRequestURITooLong)
+	ErrUnsupportedMediaType         = statusError(http.StatusUnsupportedMediaType)
+	ErrRequestedRangeNotSatisfiable = statusError(http.StatusRequestedRangeNotSatisfiable)
+	ErrExpectationFailed            = statusError(http.StatusExpectationFailed)
+	ErrTeapot                       = statusError(http.StatusTeapot)
+	ErrMisdirectedRequest           = statusError(http.StatusMisdirectedRequest)
+	ErrUnprocessableEntity          = statusError(http.StatusUnprocessableEntity)
+	ErrLocked                       = statusError(http.StatusLocked)
+	ErrFailedDependency             = statusError(http.StatusFailedDependency)
+	ErrTooEarly                     = statusError(http.StatusTooEarly)
+	ErrUpgradeRequired              = statusError(http.StatusUpgradeRequired)
+	ErrPreconditionRequired         = statusError(http.StatusPreconditionRequired)
+	ErrTooManyRequests              = statusError(http.StatusTooManyRequests)
+	ErrRequestHeaderFieldsTooLarge  = statusError(http.StatusRequestHeaderFieldsTooLarge)
+	ErrUnavailableForLegalReasons   = statusError(http.StatusUnavailableForLegalReasons)
+
+	ErrInternalServerError           = statusError(http.StatusInternalServerError)
+	ErrNotImplemented                = statusError(http.StatusNotImplemented)
+	ErrBadGateway                    = statusError(http.StatusBadGateway)
+	ErrServiceUnavailable            = statusError(http.StatusServiceUnavailable)
+	ErrGatewayTimeout                = statusError(http.StatusGatewayTimeout)
+	ErrHTTPVersionNotSupported       = statusError(http.StatusHTTPVersionNotSupported)
+	ErrVariantAlsoNegotiates         = statusError(http.StatusVariantAlsoNegotiates)
+	ErrInsufficientStorage           = statusError(http.StatusInsufficientStorage)
+	ErrLoopDetected                  = statusError(http.StatusLoopDetected)
+	ErrNotExtended                   = statusError(http.StatusNotExtended)
+	ErrNetworkAuthenticationRequired = statusError(http.StatusNetworkAuthenticationRequired)
+)
+
+var (
+	statusErrors = map[int]*Proxy{}
+	statusCodes  []int // ascending, the order StatusCode checks in
+)
+
+func statusError(code int) *Proxy {
+	p := New(strings.ToLower(http.StatusText(code)))
+	statusErrors[code] = p
+	statusCodes = append(statusCodes, code)
+	RegisterSentinel(p)
+	return p
+}
+
+// FromStatusCode returns the known error for an HTTP status. Unlisted statuses
+// get a generic error extending ErrBadRequest or, from 500 on,
+// ErrInternalServerError, for which StatusCode reports the actual status.
+// Statuses below 400 return nil.
+func FromStatusCode(code int) *Proxy {
+	if p, ok := statusErrors[code]; ok {
+		return p
+	}
+	if code < http.StatusBadRequest {
+		return nil
+	}
+	const format = "http status %d"
+	p := &Proxy{
+		base:    fmt.Errorf(format, code),
+		extends: &chain{errs: []error{classError(code)}, size: 1},
+		meta:    &meta{format: format, status: code},
+	}
+	return create(p, 1)
+}
+
+// classError returns the status error an unlisted status falls back to.
+func classError(code int) *Proxy {
+	if code < http.StatusInternalServerError {
+		return ErrBadRequest
+	}
+	return ErrInternalServerError
+}
+
+// StatusCode returns the HTTP status of the first status error err matches
+// via errors.Is, checking lower statuses first. For an unlisted status from
+// FromStatusCode, that is the status itself. Returns 0 if there is none.
+func StatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	for _, code := range statusCodes {
+		if errors.Is(err, statusErrors[code]) {
+			if unlisted := unlistedStatus(err); unlisted != 0 && classError(unlisted) == statusErrors[code] {
+				return unlisted
+			}
+			return code
+		}
+	}
+	return 0
+}
+
+// unlistedStatus returns the status of the outermost error in err's chain
+// created by FromStatusCode for an unlisted status, or 0.
+func unlistedStatus(err error) int {
+	var status int
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && status == 0 {
+			status = p.md().status
+		}
+	})
+	return status
+}
@@ -0,0 +1,45 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromStatusCode(t *testing.T) {
+	t.Parallel()
+
+	require.Same(t, ErrNotFound, FromStatusCode(http.StatusNotFound))
+	require.Same(t, ErrTooManyRequests, FromStatusCode(http.StatusTooManyRequests))
+	require.Equal(t, "service unavailable", FromStatusCode(http.StatusServiceUnavailable).Error())
+	require.Equal(t, "http status 499", FromStatusCode(499).Error())
+	require.True(t, errors.Is(FromStatusCode(499), ErrBadRequest))
+	require.True(t, errors.Is(FromStatusCode(599), ErrInternalServerError))
+	require.Equal(t, 499, StatusCode(fmt.Errorf("some context: %w", FromStatusCode(499))))
+	require.Equal(t, 599, StatusCode(FromStatusCode(599).WithOp("some.Op")))
+	require.Nil(t, FromStatusCode(http.StatusOK))
+}
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	errUserNotFound := New("some user not found").Extends(ErrNotFound)
+
+	require.Equal(t, http.StatusNotFound, StatusCode(fmt.Errorf("some context: %w", errUserNotFound)))
+	require.Equal(t, http.StatusForbidden, StatusCode(New("some error").Extends(ErrNotFound, ErrForbidden)))
+	require.Equal(t, http.StatusBadRequest, StatusCode(ErrInvalid))
+	require.Equal(t, http.StatusGatewayTimeout, StatusCode(ErrTimeout))
+	require.Zero(t, StatusCode(errors.New("some error")))
+	require.Zero(t, StatusCode(nil))
+}
+
+func TestStatusCode__round_trip(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range statusCodes {
+		require.Equal(t, code, StatusCode(FromStatusCode(code)))
+	}
+}
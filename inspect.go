@@ -0,0 +1,252 @@
+package knownerror
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// IsKnown reports whether any error in err's chain is a Proxy:
+//
+//	if !knownerror.IsKnown(err) {
+//		err = ErrInternal // hide unexpected errors from clients
+//	}
+func IsKnown(err error) bool {
+	_, ok := AsProxy(err)
+	return ok
+}
+
+// AsProxy finds the first Proxy in err's chain. The outermost Proxy wins.
+func AsProxy(err error) (*Proxy, bool) {
+	var found *Proxy
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && p != nil && found == nil {
+			found = p
+		}
+	})
+	return found, found != nil
+}
+
+// Ops collects operations recorded via WithOp across nested wraps and
+// repeated WithOp calls, outermost first:
+//
+//	err := knownerror.Wrap(repoErr).WithOp("usersvc.Profile")
+//	knownerror.Ops(err) // ["usersvc.Profile", "userrepo.Get"]
+func Ops(err error) []string {
+	var ops []string
+	walk(err, func(err error) {
+		p, ok := err.(*Proxy)
+		if !ok {
+			return
+		}
+		// Derived copies carry their parent's op; report each op once.
+		var last string
+		for q := p; q != nil; q = q.parent {
+			if q.op != "" && q.op != last {
+				ops = append(ops, q.op)
+			}
+			last = q.op
+		}
+	})
+	return ops
+}
+
+// ExtendsList returns the categories of the outermost Proxy in err's chain in
+// declaration order. Errors derived via WithCause, WithOp and similar report the
+// categories of the error they were derived from, followed by their own.
+func ExtendsList(err error) []error {
+	p, ok := AsProxy(err)
+	if !ok {
+		return nil
+	}
+	return p.extendsList()
+}
+
+func (e *Proxy) extendsList() []error {
+	var list []error
+	if e.parent != nil {
+		list = e.parent.extendsList()
+	}
+	e.extends.each(func(ext error) bool {
+		list = append(list, ext)
+		return false
+	})
+	if len(e.md().without) > 0 {
+		list = slices.DeleteFunc(list, e.excludes)
+	}
+	return list
+}
+
+// Source is a part of a Proxy searched by AsPreferred.
+type Source int
+
+const (
+	// SourceCause is the cause attached via WithCause.
+	SourceCause Source = iota
+	// SourceExtends are the extended errors, in declaration order.
+	SourceExtends
+	// SourceBase is the wrapped base error.
+	SourceBase
+)
+
+// AsPreferred finds the first error assignable to T, searching the outermost
+// Proxy's sources in the given order (cause, extends, base by default). It
+// resolves the ambiguity of errors.As when both a category and the cause
+// could satisfy T:
+//
+//	var ErrConflict = knownerror.New("conflict").Extends(&HTTPError{Code: 409})
+//	err := ErrConflict.WithCause(&HTTPError{Code: 503})
+//	e, _ := knownerror.AsPreferred[*HTTPError](err) // Code: 503
+//	e, _ = knownerror.AsPreferred[*HTTPError](err, knownerror.SourceExtends) // Code: 409
+//
+// If err's chain has no Proxy, AsPreferred behaves like errors.As.
+func AsPreferred[T any](err error, order ...Source) (T, bool) {
+	var target T
+	p, ok := AsProxy(err)
+	if !ok {
+		ok = err != nil && errors.As(err, &target)
+		return target, ok
+	}
+	if t, ok := any(p).(T); ok {
+		return t, true
+	}
+	if len(order) == 0 {
+		order = []Source{SourceCause, SourceExtends, SourceBase}
+	}
+	for _, src := range order {
+		var found bool
+		switch src {
+		case SourceCause:
+			found = p.cause != nil && errors.As(p.cause, &target)
+		case SourceExtends:
+			found = p.As(&target)
+		case SourceBase:
+			found = p.base != nil && errors.As(p.base, &target)
+		}
+		if found {
+			return target, true
+		}
+	}
+	return target, false
+}
+
+// IDOf returns the instance ID of the outermost Proxy in err's chain that has one.
+func IDOf(err error) string {
+	return firstOf(err, (*Proxy).ID)
+}
+
+// HelpURLOf returns the help URL of the outermost Proxy in err's chain that has one.
+func HelpURLOf(err error) string {
+	return firstOf(err, (*Proxy).HelpURL)
+}
+
+// HintOf returns the remediation hint of the outermost Proxy in err's chain that has one.
+func HintOf(err error) string {
+	return firstOf(err, (*Proxy).Hint)
+}
+
+// DomainOf returns the subsystem of the outermost Proxy in err's chain that has
+// one, including causes.
+func DomainOf(err error) string {
+	return firstOf(err, (*Proxy).Domain)
+}
+
+// RetryAfterOf returns the retry delay of the outermost Proxy in err's chain
+// that has one, or 0.
+func RetryAfterOf(err error) time.Duration {
+	var d time.Duration
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && d == 0 {
+			d = p.md().retry
+		}
+	})
+	return d
+}
+
+// HasTag reports whether any Proxy in err's chain, including causes, is
+// tagged with tag via WithTags.
+func HasTag(err error, tag string) bool {
+	var found bool
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && !found {
+			found = slices.Contains(p.md().tags, tag)
+		}
+	})
+	return found
+}
+
+// firstOf returns the first non-empty value of get among the Proxies in err's chain.
+func firstOf(err error, get func(*Proxy) string) string {
+	var v string
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && v == "" {
+			v = get(p)
+		}
+	})
+	return v
+}
+
+var unwrappers struct {
+	mu  sync.RWMutex
+	fns []func(error) error
+}
+
+// RegisterUnwrapper teaches the package's chain-walking helpers (AsProxy, Ops,
+// IDOf, ...) to traverse errors from wrapper libraries that do not implement
+// Unwrap. fn returns the next error in the chain, or nil if it does not
+// recognize err:
+//
+//	knownerror.RegisterUnwrapper(func(err error) error {
+//		if c, ok := err.(interface{ Cause() error }); ok {
+//			return c.Cause()
+//		}
+//		return nil
+//	})
+//
+// Register unwrappers during program initialization.
+func RegisterUnwrapper(fn func(error) error) {
+	if fn == nil {
+		return
+	}
+	unwrappers.mu.Lock()
+	defer unwrappers.mu.Unlock()
+	unwrappers.fns = append(unwrappers.fns, fn)
+}
+
+// walk visits err and everything reachable from it through Unwrap, Proxy
+// causes and registered unwrappers, depth first, up to maxDepth levels deep.
+func walk(err error, visit func(error)) {
+	walkDepth(err, visit, 0)
+}
+
+func walkDepth(err error, visit func(error), depth int) {
+	if err == nil || depth > maxDepth {
+		return
+	}
+	visit(err)
+	switch x := err.(type) {
+	case *Proxy:
+		walkDepth(x.base, visit, depth+1)
+		walkDepth(x.cause, visit, depth+1)
+	case interface{ Unwrap() error }:
+		walkDepth(x.Unwrap(), visit, depth+1)
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			walkDepth(err, visit, depth+1)
+		}
+	default:
+		walkDepth(unwrapRegistered(err), visit, depth+1)
+	}
+}
+
+func unwrapRegistered(err error) error {
+	unwrappers.mu.RLock()
+	defer unwrappers.mu.RUnlock()
+	for _, fn := range unwrappers.fns {
+		if next := fn(err); next != nil {
+			return next
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKnown(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error")
+	require.True(t, IsKnown(err))
+}
+
+func TestIsKnown__wrapped(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("some context: %w", New("some error"))
+	require.True(t, IsKnown(err))
+}
+
+func TestIsKnown__unknown(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, IsKnown(errors.New("some error")))
+	require.False(t, IsKnown(nil))
+}
+
+func TestAsProxy(t *testing.T) {
+	t.Parallel()
+
+	inner := New("some inner error")
+	outer := New("some outer error").WithCause(inner)
+	err := fmt.Errorf("some context: %w", outer)
+
+	p, ok := AsProxy(err)
+	require.True(t, ok)
+	require.Same(t, outer, p)
+}
+
+func TestAsProxy__unknown(t *testing.T) {
+	t.Parallel()
+
+	p, ok := AsProxy(errors.New("some error"))
+	require.False(t, ok)
+	require.Nil(t, p)
+}
+
+func TestOps(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some not found error")
+	repoErr := sentinel.WithCause(errors.New("some cause")).WithOp("userrepo.Get")
+	svcErr := Wrap(fmt.Errorf("some context: %w", repoErr)).WithOp("usersvc.Profile")
+
+	require.Equal(t, []string{"usersvc.Profile", "userrepo.Get"}, Ops(svcErr))
+	require.True(t, errors.Is(svcErr, sentinel))
+}
+
+func TestOps__from_cause(t *testing.T) {
+	t.Parallel()
+
+	repoErr := New("some repo error").WithOp("userrepo.Get")
+	svcErr := New("some service error").WithCause(repoErr).WithOp("usersvc.Profile")
+
+	require.Equal(t, []string{"usersvc.Profile", "userrepo.Get"}, Ops(svcErr))
+}
+
+func TestOps__derived(t *testing.T) {
+	t.Parallel()
+
+	err := ErrNotFound.WithOp("userrepo.Get").WithID("some-id").WithOp("usersvc.Profile").WithHint("some hint")
+
+	require.Equal(t, []string{"usersvc.Profile", "userrepo.Get"}, Ops(err))
+	require.Equal(t, []string{"usersvc.Profile", "userrepo.Get"}, Ops(Wrap(err)))
+}
+
+func TestOps__none(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Ops(New("some error")))
+	require.Empty(t, Ops(nil))
+}
+
+func TestIDOf(t *testing.T) {
+	t.Parallel()
+
+	inner := New("some inner error").WithID("some-inner-id")
+	outer := fmt.Errorf("some context: %w", Wrap(inner).WithID("some-outer-id"))
+
+	require.Equal(t, "some-outer-id", IDOf(outer))
+	require.Equal(t, "some-inner-id", IDOf(New("some error").WithCause(inner)))
+	require.Empty(t, IDOf(errors.New("some error")))
+}
+
+func TestRegisterUnwrapper(t *testing.T) {
+	sentinel := New("some error").WithOp("some.Op")
+	err := &causerError{cause: sentinel}
+
+	_, ok := AsProxy(err)
+	require.False(t, ok)
+
+	RegisterUnwrapper(func(err error) error {
+		if c, ok := err.(*causerError); ok {
+			return c.cause
+		}
+		return nil
+	})
+
+	p, ok := AsProxy(err)
+	require.True(t, ok)
+	require.Same(t, sentinel, p)
+	require.Equal(t, []string{"some.Op"}, Ops(err))
+}
+
+type causerError struct {
+	cause error
+}
+
+func (e *causerError) Error() string {
+	return "some causer error: " + e.cause.Error()
+}
+
+func TestExtendsList(t *testing.T) {
+	t.Parallel()
+
+	ext1 := errors.New("some first extension")
+	ext2 := errors.New("some second extension")
+	ext3 := errors.New("some third extension")
+	sentinel := New("some error").Extends(ext1, ext2)
+	err := fmt.Errorf("some context: %w", sentinel.WithCause(errors.New("some cause")).Extends(ext3))
+
+	require.Equal(t, []error{ext1, ext2, ext3}, ExtendsList(err))
+	require.Equal(t, []error{ext1, ext2}, ExtendsList(sentinel))
+}
+
+func TestExtendsList__none(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, ExtendsList(New("some error")))
+	require.Nil(t, ExtendsList(errors.New("some error")))
+}
+
+func TestAsPreferred(t *testing.T) {
+	t.Parallel()
+
+	category := &customError{code: 409}
+	cause := &customError{code: 503}
+	err := fmt.Errorf("some context: %w", New("some error").Extends(category).WithCause(cause))
+
+	target, ok := AsPreferred[*customError](err)
+	require.True(t, ok)
+	require.Same(t, cause, target)
+
+	target, ok = AsPreferred[*customError](err, SourceExtends, SourceCause)
+	require.True(t, ok)
+	require.Same(t, category, target)
+
+	_, ok = AsPreferred[*customError](err, SourceBase)
+	require.False(t, ok)
+}
+
+func TestAsPreferred__proxy_itself(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").WithCause(New("some cause"))
+
+	target, ok := AsPreferred[*Proxy](err)
+	require.True(t, ok)
+	require.Same(t, err, target)
+}
+
+func TestAsPreferred__no_proxy(t *testing.T) {
+	t.Parallel()
+
+	custom := &customError{code: 8234}
+	target, ok := AsPreferred[*customError](fmt.Errorf("some context: %w", custom))
+	require.True(t, ok)
+	require.Same(t, custom, target)
+
+	_, ok = AsPreferred[*customError](nil)
+	require.False(t, ok)
+}
+
+func TestHelpURLOf(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithHelpURL("https://example.com/some-help")
+	err := fmt.Errorf("some context: %w", sentinel.WithCause(errors.New("some cause")))
+
+	require.Equal(t, "https://example.com/some-help", HelpURLOf(err))
+	require.Empty(t, HelpURLOf(errors.New("some error")))
+}
+
+func TestHintOf(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithHint("some hint")
+	err := fmt.Errorf("some context: %w", Wrap(sentinel).WithOp("some.Op"))
+
+	require.Equal(t, "some hint", HintOf(err))
+	require.Empty(t, HintOf(errors.New("some error")))
+}
+
+func TestHasTag(t *testing.T) {
+	t.Parallel()
+
+	cause := New("some cause").WithTags("external")
+	err := fmt.Errorf("some context: %w", New("some error").WithTags("billing").WithCause(cause))
+
+	require.True(t, HasTag(err, "billing"))
+	require.True(t, HasTag(err, "external"))
+	require.False(t, HasTag(err, "some-tag"))
+	require.False(t, HasTag(errors.New("some error"), "billing"))
+}
+
+func TestDomainOf(t *testing.T) {
+	t.Parallel()
+
+	cause := New("some cause").WithDomain("some-cause-domain")
+	sentinel := New("some error").WithDomain("some-domain")
+
+	require.Equal(t, "some-domain", DomainOf(fmt.Errorf("some context: %w", Wrap(sentinel).WithCause(cause))))
+	require.Equal(t, "some-cause-domain", DomainOf(New("some error").WithCause(cause)))
+	require.Empty(t, DomainOf(errors.New("some error")))
+}
+
+func TestRetryAfterOf(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithRetryAfter(time.Minute)
+	err := fmt.Errorf("some context: %w", Wrap(sentinel).WithOp("some.Op"))
+
+	require.Equal(t, time.Minute, RetryAfterOf(err))
+	require.Zero(t, RetryAfterOf(errors.New("some error")))
+}
@@ -0,0 +1,77 @@
+// Package kcli presents known errors to command-line users and exits with the
+// mapped exit code. It plugs into cobra's RunE return path:
+//
+//	cmd := &cobra.Command{SilenceErrors: true, SilenceUsage: true, RunE: run}
+//	kcli.Handle(cmd.Execute())
+package kcli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pprishchepa/knownerror"
+)
+
+// Handler prints errors and exits. The zero value prints to os.Stderr and exits
+// via os.Exit.
+type Handler struct {
+	// Out receives the output. Defaults to os.Stderr.
+	Out io.Writer
+	// Verbose also prints the error details and the operation trace.
+	Verbose bool
+	// Exit is called with the exit code. Defaults to os.Exit.
+	Exit func(code int)
+}
+
+// Handle prints err and exits with knownerror.ExitCodeOf(err). It does nothing
+// if err is nil.
+func (h Handler) Handle(err error) {
+	if err == nil {
+		return
+	}
+	out, exit := h.Out, h.Exit
+	if out == nil {
+		out = os.Stderr
+	}
+	if exit == nil {
+		exit = os.Exit
+	}
+	Print(out, err, h.Verbose)
+	exit(knownerror.ExitCodeOf(err))
+}
+
+// Handle prints err to os.Stderr and exits with knownerror.ExitCodeOf(err).
+// It does nothing if err is nil.
+func Handle(err error) {
+	Handler{}.Handle(err)
+}
+
+// Print writes err, its hint and help URL to w. With verbose, it also writes
+// the outermost Proxy's details and the operations recorded via WithOp:
+//
+//	Error: deploy failed
+//	Hint: check that the API key has the deploy scope
+//	Help: https://example.com/docs/errors/deploy
+func Print(w io.Writer, err error, verbose bool) {
+	if err == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Error: %s\n", knownerror.Sanitize(err.Error()))
+	if hint := knownerror.HintOf(err); hint != "" {
+		_, _ = fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+	if url := knownerror.HelpURLOf(err); url != "" {
+		_, _ = fmt.Fprintf(w, "Help: %s\n", url)
+	}
+	if !verbose {
+		return
+	}
+	if p, ok := knownerror.AsProxy(err); ok {
+		_, _ = fmt.Fprintf(w, "Details: %+v\n", p)
+	}
+	if ops := knownerror.Ops(err); len(ops) > 0 {
+		_, _ = fmt.Fprintf(w, "Trace: %s\n", strings.Join(ops, " <- "))
+	}
+}
@@ -0,0 +1,60 @@
+package kcli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pprishchepa/knownerror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	var code int
+	h := Handler{Out: &out, Exit: func(c int) { code = c }}
+
+	err := knownerror.New("some error").
+		WithHint("some hint").
+		WithHelpURL("https://example.com/some-help").
+		WithExitCode(3)
+	h.Handle(err)
+
+	require.Equal(t, 3, code)
+	require.Equal(t, "Error: some error\nHint: some hint\nHelp: https://example.com/some-help\n", out.String())
+}
+
+func TestHandler_Handle__nil(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	h := Handler{Out: &out, Exit: func(int) { t.Fatal("unexpected exit") }}
+	h.Handle(nil)
+
+	require.Empty(t, out.String())
+}
+
+func TestHandler_Handle__verbose(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	var code int
+	h := Handler{Out: &out, Verbose: true, Exit: func(c int) { code = c }}
+
+	repoErr := knownerror.ErrNotFound.WithCause(errors.New("some cause")).WithOp("some.Inner")
+	h.Handle(knownerror.Wrap(repoErr).WithOp("some.Outer"))
+
+	require.Equal(t, 4, code)
+	require.Equal(t, "Error: not found\nDetails: not found (cause: some cause)\nTrace: some.Outer <- some.Inner\n", out.String())
+}
+
+func TestPrint__unknown(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	Print(&out, errors.New("some error"), true)
+
+	require.Equal(t, "Error: some error\n", out.String())
+}
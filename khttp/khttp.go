@@ -0,0 +1,299 @@
+// Package khttp renders known errors as HTTP responses.
+package khttp
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pprishchepa/knownerror"
+)
+
+// Media types offered by WriteError, in order of preference.
+const (
+	ContentTypeProblem = "application/problem+json"
+	ContentTypeJSON    = "application/json"
+	ContentTypeText    = "text/plain"
+)
+
+// Problem is the RFC 9457 problem details body written by WriteError.
+type Problem struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+	HelpURL string `json:"help_url,omitempty"`
+	// Fields maps field paths to messages for a knownerror.ValidationError.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Quota describes the quota of a knownerror.QuotaExceeded error.
+	Quota *Quota `json:"quota,omitempty"`
+}
+
+// Quota is the problem details extension member for an exhausted quota.
+type Quota struct {
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     string `json:"reset,omitempty"` // RFC 3339
+}
+
+// WriteError writes err as an HTTP response, choosing problem+json, plain JSON
+// or text from the request's Accept header. The status comes from
+// knownerror.DefaultMapper. The body carries the public message of the
+// outermost Proxy, its ID, public hint and help URL; messages of unknown errors
+// and the metadata of causes are never exposed. The headers are set by SetHeaders:
+//
+//	func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		if err := h.serve(w, r); err != nil {
+//			khttp.WriteError(w, r, err)
+//		}
+//	}
+//
+// WriteError does nothing if err is nil.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	p := NewProblem(err)
+
+	h := w.Header()
+	h.Set("X-Content-Type-Options", "nosniff")
+	SetHeaders(h, err)
+
+	contentType := negotiate(r.Header.Get("Accept"))
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(p.Status)
+
+	switch contentType {
+	case ContentTypeText:
+		_, _ = w.Write([]byte(p.Detail + "\n"))
+	case ContentTypeJSON:
+		_ = json.NewEncoder(w).Encode(struct {
+			Message string            `json:"message"`
+			Status  int               `json:"status"`
+			ID      string            `json:"id,omitempty"`
+			Hint    string            `json:"hint,omitempty"`
+			HelpURL string            `json:"help_url,omitempty"`
+			Fields  map[string]string `json:"fields,omitempty"`
+			Quota   *Quota            `json:"quota,omitempty"`
+		}{p.Detail, p.Status, p.ID, p.Hint, p.HelpURL, p.Fields, p.Quota})
+	default:
+		_ = json.NewEncoder(w).Encode(p)
+	}
+}
+
+// NewProblem describes err as problem details. The type is the help URL, or
+// "about:blank" if there is none. The detail is the message of the outermost
+// Proxy, or the status text for unknown errors.
+//
+// The ID, hint and help URL come from the outermost Proxy and the Proxies it
+// wraps, never from causes or categories. Only hints set via WithPublicHint are
+// exposed.
+//
+// A knownerror.ValidationError is described by knownerror.ErrInvalid, with the
+// messages of its field errors in Fields; the metadata of the field errors is
+// not exposed. The details of a knownerror.QuotaExceeded error are in Quota.
+func NewProblem(err error) Problem {
+	status := knownerror.DefaultMapper.HTTPStatus(err)
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: http.StatusText(status),
+	}
+	var v *knownerror.ValidationError
+	if errors.As(err, &v) {
+		p.Detail = knownerror.ErrInvalid.Message()
+		p.Fields = fields(v)
+	}
+	if proxy, ok := knownerror.AsProxy(err); ok && v == nil {
+		p.Detail = proxy.Message()
+		p.ID, p.Hint, p.HelpURL = metadata(proxy)
+	} else if ok && wraps(proxy, v) {
+		// Not the message of the Proxy wrapping v: it repeats the fields.
+		p.ID, p.Hint, p.HelpURL = metadata(proxy)
+	}
+	if d, ok := knownerror.QuotaOf(err); ok {
+		p.Quota = &Quota{Limit: d.Limit, Remaining: d.Remaining}
+		if !d.Reset.IsZero() {
+			p.Quota.Reset = d.Reset.UTC().Format(time.RFC3339)
+		}
+	}
+	if p.HelpURL != "" {
+		p.Type = p.HelpURL
+	}
+	return p
+}
+
+// metadata returns the ID, public hint and help URL of proxy, falling back to
+// the Proxies it wraps. Causes are not searched: their metadata is internal.
+func metadata(proxy *knownerror.Proxy) (id, hint, helpURL string) {
+	for err := error(proxy); err != nil; err = errors.Unwrap(err) {
+		p, ok := err.(*knownerror.Proxy)
+		if !ok || p == nil {
+			continue
+		}
+		if id == "" {
+			id = p.ID()
+		}
+		if hint == "" {
+			hint = p.PublicHint()
+		}
+		if helpURL == "" {
+			helpURL = p.HelpURL()
+		}
+	}
+	return id, hint, helpURL
+}
+
+// fields returns the messages of v's field errors by path. Messages for the
+// same path are joined with "; ".
+func fields(v *knownerror.ValidationError) map[string]string {
+	m := make(map[string]string, len(v.Fields))
+	for _, f := range v.Fields {
+		if msg, ok := m[f.Path]; ok {
+			m[f.Path] = msg + "; " + f.Err.Message()
+		} else {
+			m[f.Path] = f.Err.Message()
+		}
+	}
+	return m
+}
+
+// wraps reports whether v is part of proxy's chain rather than proxy being
+// one of v's field errors.
+func wraps(proxy *knownerror.Proxy, v *knownerror.ValidationError) bool {
+	var inner *knownerror.ValidationError
+	return errors.As(proxy, &inner) && inner == v
+}
+
+// negotiate picks the offered media type the Accept header prefers, falling
+// back to problem+json.
+func negotiate(accept string) string {
+	offers := [...]string{ContentTypeProblem, ContentTypeJSON, ContentTypeText}
+	best, bestQ := ContentTypeProblem, 0.0
+	for _, offer := range offers {
+		if q := quality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// quality returns the q-value the Accept header assigns to mediaType, using the
+// most specific matching range. An empty header accepts everything.
+func quality(accept, mediaType string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, rng := range strings.Split(accept, ",") {
+		params := strings.Split(rng, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		var s int
+		switch name {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		for _, param := range params[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+	}
+	return q
+}
+
+// Headers set by SetHeaders and read by FromResponse.
+const (
+	HeaderID        = "X-Error-Id"
+	HeaderRetryable = "X-Error-Retryable"
+)
+
+// SetHeaders describes err in response headers, so that even responses
+// without an error body (streams, HEAD requests, proxies replacing the body)
+// let clients reconstruct it with FromResponse:
+//
+//   - X-Error-Id: the instance ID set via WithID or WithNewID;
+//   - X-Error-Retryable: whether knownerror.IsTransient reports true;
+//   - Retry-After: the delay set via WithRetryAfter, in seconds.
+//
+// Call it before writing the status. It does nothing if err is nil.
+func SetHeaders(h http.Header, err error) {
+	if err == nil {
+		return
+	}
+	if id := knownerror.IDOf(err); id != "" {
+		h.Set(HeaderID, id)
+	}
+	h.Set(HeaderRetryable, strconv.FormatBool(knownerror.IsTransient(err)))
+	if d := knownerror.RetryAfterOf(err); d > 0 {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+}
+
+// FromResponse reconstructs the known error of a response with a status of 400
+// or above, and returns nil otherwise. The error matches the status category
+// (knownerror.ErrNotFound, ...) and carries the ID, retryable flag and retry
+// delay from the headers set by SetHeaders:
+//
+//	resp, err := client.Do(req)
+//	if err != nil {
+//		return err
+//	}
+//	defer resp.Body.Close()
+//	if err := khttp.FromResponse(resp); err != nil {
+//		return err // errors.Is(err, knownerror.ErrNotFound), knownerror.IDOf(err), ...
+//	}
+//
+// The body is not read.
+func FromResponse(resp *http.Response) error {
+	p := knownerror.FromStatusCode(resp.StatusCode)
+	if p == nil {
+		return nil
+	}
+	h := resp.Header
+	if id := h.Get(HeaderID); id != "" {
+		p = p.WithID(id)
+	}
+	if retryable, err := strconv.ParseBool(h.Get(HeaderRetryable)); err == nil {
+		p = p.WithTemporary(retryable)
+	}
+	if d := parseRetryAfter(h.Get("Retry-After")); d > 0 {
+		p = p.WithRetryAfter(d)
+	}
+	return p
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
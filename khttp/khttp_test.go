@@ -0,0 +1,251 @@
+package khttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/knownerror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	err := knownerror.New("user not found").
+		Extends(knownerror.ErrNotFound).
+		WithCause(errors.New("some secret cause")).
+		WithCauseInMessage(true).
+		WithID("some-id").
+		WithPublicHint("some hint").
+		WithHelpURL("https://example.com/some-help")
+
+	tests := []struct {
+		name string
+		got  struct {
+			accept string
+		}
+		want struct {
+			contentType string
+			body        string
+		}
+	}{
+		{
+			name: "problem_by_default",
+			got: struct {
+				accept string
+			}{
+				accept: "",
+			},
+			want: struct {
+				contentType string
+				body        string
+			}{
+				contentType: "application/problem+json; charset=utf-8",
+				body:        `{"type":"https://example.com/some-help","title":"Not Found","status":404,"detail":"user not found","id":"some-id","hint":"some hint","help_url":"https://example.com/some-help"}` + "\n",
+			},
+		},
+		{
+			name: "json",
+			got: struct {
+				accept string
+			}{
+				accept: "application/json",
+			},
+			want: struct {
+				contentType string
+				body        string
+			}{
+				contentType: "application/json; charset=utf-8",
+				body:        `{"message":"user not found","status":404,"id":"some-id","hint":"some hint","help_url":"https://example.com/some-help"}` + "\n",
+			},
+		},
+		{
+			name: "text_preferred",
+			got: struct {
+				accept string
+			}{
+				accept: "application/json;q=0.5, text/*",
+			},
+			want: struct {
+				contentType string
+				body        string
+			}{
+				contentType: "text/plain; charset=utf-8",
+				body:        "user not found\n",
+			},
+		},
+		{
+			name: "unsupported",
+			got: struct {
+				accept string
+			}{
+				accept: "image/png",
+			},
+			want: struct {
+				contentType string
+				body        string
+			}{
+				contentType: "application/problem+json; charset=utf-8",
+				body:        `{"type":"https://example.com/some-help","title":"Not Found","status":404,"detail":"user not found","id":"some-id","hint":"some hint","help_url":"https://example.com/some-help"}` + "\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.got.accept)
+			w := httptest.NewRecorder()
+			WriteError(w, r, err)
+
+			require.Equal(t, http.StatusNotFound, w.Code)
+			require.Equal(t, tt.want.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.want.body, w.Body.String())
+		})
+	}
+}
+
+func TestWriteError__unknown(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	WriteError(w, r, errors.New("some secret error"))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Internal Server Error"}`+"\n", w.Body.String())
+}
+
+func TestNewProblem__newf_cause(t *testing.T) {
+	t.Parallel()
+
+	err := knownerror.Newf("loading user: %w", errors.New("some secret cause"))
+	require.Equal(t, "loading user", NewProblem(err).Detail)
+}
+
+func TestNewProblem__internal_metadata(t *testing.T) {
+	t.Parallel()
+
+	cause := knownerror.New("some cause").
+		WithID("some-cause-id").
+		WithPublicHint("some cause hint").
+		WithHelpURL("https://example.com/some-internal-help")
+	err := knownerror.New("user not found").
+		Extends(knownerror.ErrNotFound).
+		WithHint("some internal hint").
+		WithCause(cause)
+
+	p := NewProblem(err)
+	require.Equal(t, "about:blank", p.Type)
+	require.Empty(t, p.ID)
+	require.Empty(t, p.Hint)
+	require.Empty(t, p.HelpURL)
+
+	p = NewProblem(knownerror.Wrap(err.WithPublicHint("some hint").WithID("some-id")))
+	require.Equal(t, "some-id", p.ID)
+	require.Equal(t, "some hint", p.Hint)
+	require.Empty(t, p.HelpURL)
+}
+
+func TestNewProblem__validation(t *testing.T) {
+	t.Parallel()
+
+	var v knownerror.ValidationError
+	v.Add("name", knownerror.New("is required").WithCause(errors.New("some secret cause")).WithCauseInMessage(true))
+	v.Add("password", knownerror.New("is too short").WithHint("some field hint"))
+	v.Add("password", knownerror.New("is too common"))
+
+	p := NewProblem(v.Err())
+	require.Equal(t, http.StatusBadRequest, p.Status)
+	require.Equal(t, "invalid input", p.Detail)
+	require.Empty(t, p.Hint)
+	require.Equal(t, map[string]string{"name": "is required", "password": "is too short; is too common"}, p.Fields)
+
+	p = NewProblem(knownerror.Wrap(v.Err()).WithID("some-id"))
+	require.Equal(t, "invalid input", p.Detail)
+	require.Equal(t, "some-id", p.ID)
+	require.Empty(t, p.Hint)
+	require.Len(t, p.Fields, 2)
+}
+
+func TestWriteError__quota(t *testing.T) {
+	t.Parallel()
+
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	WriteError(w, r, knownerror.QuotaExceeded(knownerror.QuotaDetail{Limit: 100, Reset: reset}))
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"resource exhausted","quota":{"limit":100,"remaining":0,"reset":"`+reset.UTC().Format(time.RFC3339)+`"}}`+"\n", w.Body.String())
+}
+
+func TestWriteError__retry_after(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	WriteError(w, r, knownerror.ErrTooManyRequests.WithRetryAfter(1500*time.Millisecond))
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestWriteError__nil(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	WriteError(w, r, nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Body.String())
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	WriteError(w, httptest.NewRequest(http.MethodGet, "/", nil),
+		knownerror.ErrServiceUnavailable.WithID("some-id").WithRetryAfter(time.Minute))
+
+	require.Equal(t, "some-id", w.Header().Get(HeaderID))
+	require.Equal(t, "true", w.Header().Get(HeaderRetryable))
+
+	err := FromResponse(w.Result())
+	require.True(t, errors.Is(err, knownerror.ErrServiceUnavailable))
+	require.Equal(t, "some-id", knownerror.IDOf(err))
+	require.Equal(t, time.Minute, knownerror.RetryAfterOf(err))
+	require.True(t, knownerror.IsTransient(err))
+}
+
+func TestFromResponse__not_retryable(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	resp.Header.Set(HeaderRetryable, "false")
+
+	err := FromResponse(resp)
+	require.True(t, errors.Is(err, knownerror.ErrServiceUnavailable))
+	require.False(t, knownerror.IsTransient(err))
+}
+
+func TestFromResponse__success(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, FromResponse(&http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}}))
+}
+
+func TestSetHeaders__nil(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	SetHeaders(h, nil)
+
+	require.Empty(t, h)
+}
@@ -0,0 +1,16 @@
+module github.com/pprishchepa/knownerror/klogr
+
+go 1.23
+
+require (
+	github.com/go-logr/logr v1.4.4
+	github.com/pprishchepa/knownerror v0.1.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,71 @@
+// Package klogr integrates knownerror with logr, the logging interface used by
+// Kubernetes controllers and operators.
+package klogr
+
+import (
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/pprishchepa/knownerror"
+)
+
+// KVList expands err into logr key/value pairs, prefixing the keys returned by
+// knownerror.KeyValues with "error_":
+//
+//	logger.Error(err, "reconcile failed", klogr.KVList(err)...)
+func KVList(err error) []any {
+	kv := knownerror.KeyValues(err)
+	for i := 0; i < len(kv); i += 2 {
+		kv[i] = "error_" + kv[i].(string)
+	}
+	return kv
+}
+
+// NewSink wraps sink so that every Error call is enriched with KVList(err):
+//
+//	logger := logr.New(klogr.NewSink(zapr.NewLogger(z).GetSink()))
+func NewSink(sink logr.LogSink) logr.LogSink {
+	if _, ok := sink.(logr.CallDepthLogSink); ok {
+		return callDepthSink{errorSink{sink}}
+	}
+	return errorSink{sink}
+}
+
+type errorSink struct {
+	sink logr.LogSink
+}
+
+func (s errorSink) Init(info logr.RuntimeInfo) {
+	// Account for the wrapper's own frame in caller lookups.
+	info.CallDepth++
+	s.sink.Init(info)
+}
+
+func (s errorSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s errorSink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s errorSink) Error(err error, msg string, keysAndValues ...any) {
+	s.sink.Error(err, msg, slices.Concat(keysAndValues, KVList(err))...)
+}
+
+func (s errorSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return NewSink(s.sink.WithValues(keysAndValues...))
+}
+
+func (s errorSink) WithName(name string) logr.LogSink {
+	return NewSink(s.sink.WithName(name))
+}
+
+// callDepthSink keeps the wrapped sink's logr.CallDepthLogSink support.
+type callDepthSink struct {
+	errorSink
+}
+
+func (s callDepthSink) WithCallDepth(depth int) logr.LogSink {
+	return NewSink(s.sink.(logr.CallDepthLogSink).WithCallDepth(depth))
+}
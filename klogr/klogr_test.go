@@ -0,0 +1,58 @@
+package klogr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/pprishchepa/knownerror"
+	"github.com/pprishchepa/knownerror/klogr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVList(t *testing.T) {
+	t.Parallel()
+
+	err := knownerror.New("some error").WithID("some-id")
+	require.Equal(t, []any{"error_message", "some error", "error_id", "some-id"}, klogr.KVList(err))
+	require.Nil(t, klogr.KVList(nil))
+}
+
+func TestNewSink(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	base := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	logger := logr.New(klogr.NewSink(base.GetSink())).WithName("some-name").WithValues("some-key", "some-value")
+
+	err := knownerror.New("some error").WithCause(errors.New("some cause"))
+	logger.Error(err, "some message")
+	logger.Info("some info")
+
+	require.Equal(t, []string{
+		`"msg"="some message" "error"="some error" "some-key"="some-value" "error_message"="some error" "error_cause"="some cause"`,
+		`"level"=0 "msg"="some info" "some-key"="some-value"`,
+	}, lines)
+}
+
+func TestNewSink__call_depth(t *testing.T) {
+	t.Parallel()
+
+	sink := klogr.NewSink(funcr.New(func(prefix, args string) {}, funcr.Options{}).GetSink())
+	_, ok := sink.(logr.CallDepthLogSink)
+	require.True(t, ok)
+}
+
+func TestNewSink__caller_values(t *testing.T) {
+	t.Parallel()
+
+	sink := klogr.NewSink(funcr.New(func(prefix, args string) {}, funcr.Options{}).GetSink())
+	kv := make([]any, 2, 4)
+	kv[0], kv[1] = "some-key", "some-value"
+	sink.Error(knownerror.New("some error"), "some message", kv...)
+
+	require.Equal(t, []any{nil, nil}, kv[2:4])
+}
@@ -0,0 +1,91 @@
+// Package knownerrortest provides helpers for testing code that returns known errors.
+package knownerrortest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pprishchepa/knownerror"
+)
+
+// Diff compares two errors field by field (message, operation, hint, help
+// URL, domain, tags, retry delay, exit code, timeout and temporary flags,
+// categories and the cause chain) and describes the differences, one per line.
+// It returns "" if the errors are equivalent:
+//
+//	if diff := knownerrortest.Diff(want, err); diff != "" {
+//		t.Errorf("unexpected error (-want +got):\n%s", diff)
+//	}
+//
+// Errors without a Proxy are compared by message. Instance IDs and
+// timestamps are ignored, since they differ between occurrences.
+func Diff(want, got error) string {
+	var b strings.Builder
+	diff(&b, "", want, got)
+	return b.String()
+}
+
+func diff(b *strings.Builder, prefix string, want, got error) {
+	if want == nil || got == nil {
+		if want != got {
+			line(b, prefix+"error", describe(want), describe(got))
+		}
+		return
+	}
+	wp, wantKnown := knownerror.AsProxy(want)
+	gp, gotKnown := knownerror.AsProxy(got)
+	if !wantKnown || !gotKnown {
+		if wantKnown != gotKnown {
+			line(b, prefix+"known", fmt.Sprint(wantKnown), fmt.Sprint(gotKnown))
+		}
+		if want.Error() != got.Error() {
+			line(b, prefix+"message", want.Error(), got.Error())
+		}
+		return
+	}
+	field(b, prefix+"message", wp.Error(), gp.Error())
+	field(b, prefix+"op", wp.Op(), gp.Op())
+	field(b, prefix+"hint", wp.Hint(), gp.Hint())
+	field(b, prefix+"public_hint", wp.PublicHint(), gp.PublicHint())
+	field(b, prefix+"help_url", wp.HelpURL(), gp.HelpURL())
+	field(b, prefix+"domain", wp.Domain(), gp.Domain())
+	field(b, prefix+"tags", fmt.Sprint(wp.Tags()), fmt.Sprint(gp.Tags()))
+	field(b, prefix+"retry_after", wp.RetryAfter().String(), gp.RetryAfter().String())
+	field(b, prefix+"exit_code", fmt.Sprint(wp.ExitCode()), fmt.Sprint(gp.ExitCode()))
+	field(b, prefix+"timeout", fmt.Sprint(knownerror.IsTimeout(wp)), fmt.Sprint(knownerror.IsTimeout(gp)))
+	field(b, prefix+"temporary", fmt.Sprint(temporary(wp)), fmt.Sprint(temporary(gp)))
+	field(b, prefix+"categories", categories(wp), categories(gp))
+	diff(b, prefix+"cause.", wp.Cause(), gp.Cause())
+}
+
+func field(b *strings.Builder, name, want, got string) {
+	if want != got {
+		line(b, name, want, got)
+	}
+}
+
+func line(b *strings.Builder, name, want, got string) {
+	fmt.Fprintf(b, "%s: -%q +%q\n", name, want, got)
+}
+
+func temporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+func describe(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func categories(p *knownerror.Proxy) string {
+	list := knownerror.ExtendsList(p)
+	msgs := make([]string, len(list))
+	for i, err := range list {
+		msgs[i] = err.Error()
+	}
+	return "[" + strings.Join(msgs, ", ") + "]"
+}
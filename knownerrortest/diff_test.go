@@ -0,0 +1,134 @@
+package knownerrortest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/knownerror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("some not found")
+	errExternal := errors.New("some external")
+	sentinel := knownerror.New("some error").Extends(errNotFound)
+
+	tests := []struct {
+		name string
+		got  struct {
+			want error
+			got  error
+		}
+		want struct {
+			diff string
+		}
+	}{
+		{
+			name: "equal",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel.WithCause(errors.New("some cause")).WithNewID(),
+				got:  sentinel.WithCause(errors.New("some cause")).WithNewID(),
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "",
+			},
+		},
+		{
+			name: "fields",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel.WithOp("some.Op").WithHint("some hint"),
+				got:  sentinel.WithOp("some.OtherOp").Extends(errExternal),
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "op: -\"some.Op\" +\"some.OtherOp\"\n" +
+					"hint: -\"some hint\" +\"\"\n" +
+					"categories: -\"[some not found]\" +\"[some not found, some external]\"\n",
+			},
+		},
+		{
+			name: "metadata",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel.WithTags("some-tag").WithDomain("some-domain").WithRetryAfter(time.Second).WithTimeout(true),
+				got:  sentinel.WithTags("some-tag", "some-other-tag").WithTemporary(true),
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "domain: -\"some-domain\" +\"\"\n" +
+					"tags: -\"[some-tag]\" +\"[some-tag some-other-tag]\"\n" +
+					"retry_after: -\"1s\" +\"0s\"\n" +
+					"timeout: -\"true\" +\"false\"\n" +
+					"temporary: -\"false\" +\"true\"\n",
+			},
+		},
+		{
+			name: "cause_chain",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel.WithCause(knownerror.New("some cause").WithCause(errors.New("some root"))),
+				got:  sentinel.WithCause(knownerror.New("some cause").WithCause(errors.New("some other root"))),
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "cause.cause.message: -\"some root\" +\"some other root\"\n",
+			},
+		},
+		{
+			name: "unknown",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel,
+				got:  errors.New("some error"),
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "known: -\"true\" +\"false\"\n",
+			},
+		},
+		{
+			name: "nil",
+			got: struct {
+				want error
+				got  error
+			}{
+				want: sentinel,
+				got:  nil,
+			},
+			want: struct {
+				diff string
+			}{
+				diff: "error: -\"some error\" +\"<nil>\"\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want.diff, Diff(tt.got.want, tt.got.got))
+		})
+	}
+}
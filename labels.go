@@ -0,0 +1,51 @@
+package knownerror
+
+import "strconv"
+
+// Labels returns a bounded set of metric labels for err, suitable for
+// Prometheus or StatsD. The keys are always the same, so label sets stay
+// consistent across errors:
+//
+//   - "known": whether err's chain has a Proxy;
+//   - "category": the message template of the first category that is a Proxy,
+//     e.g. "not found", or "";
+//   - "domain": the subsystem set via WithDomain, or "";
+//   - "status": the HTTP status from DefaultMapper;
+//   - "transient": whether IsTransient reports true.
+//
+// Messages, IDs and other dynamic values are never used: categories are
+// represented by their Newf templates, not their formatted messages.
+//
+//	errorsTotal.With(knownerror.Labels(err)).Inc()
+//
+// Returns nil for a nil error.
+func Labels(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	var category string
+	p, known := AsProxy(err)
+	if known {
+		category = firstCategory(p)
+	}
+	return map[string]string{
+		"known":     strconv.FormatBool(known),
+		"category":  category,
+		"domain":    DomainOf(err),
+		"status":    strconv.Itoa(DefaultMapper.HTTPStatus(err)),
+		"transient": strconv.FormatBool(IsTransient(err)),
+	}
+}
+
+// firstCategory returns the template of the first Proxy category of p or of
+// the Proxies it wraps.
+func firstCategory(p *Proxy) string {
+	for q := p; q != nil; q, _ = q.base.(*Proxy) {
+		for _, ext := range q.extendsList() {
+			if c, ok := ext.(*Proxy); ok && c != nil {
+				return c.template()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	errUserNotFound := New("user not found").Extends(ErrNotFound).WithDomain("some-domain")
+	err := fmt.Errorf("some context: %w", Wrap(errUserNotFound.WithCause(errors.New("some cause"))).WithNewID())
+
+	require.Equal(t, map[string]string{
+		"known":     "true",
+		"category":  "not found",
+		"domain":    "some-domain",
+		"status":    "404",
+		"transient": "false",
+	}, Labels(err))
+}
+
+func TestLabels__dynamic_category(t *testing.T) {
+	t.Parallel()
+
+	category := Newf("quota %d exceeded", 42).Extends(ErrTooManyRequests)
+	err := New("some error").Extends(errors.New("some plain category"), category)
+
+	require.Equal(t, map[string]string{
+		"known":     "true",
+		"category":  "quota %d exceeded",
+		"domain":    "",
+		"status":    "429",
+		"transient": "true",
+	}, Labels(err))
+}
+
+func TestLabels__unknown(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, map[string]string{
+		"known":     "false",
+		"category":  "",
+		"domain":    "",
+		"status":    "500",
+		"transient": "false",
+	}, Labels(errors.New("some error")))
+	require.Nil(t, Labels(nil))
+}
@@ -0,0 +1,130 @@
+package knownerror
+
+import (
+	"context"
+	"log/slog"
+)
+
+// KeyValues expands err into alternating key/value pairs for structured loggers.
+// Every error yields "message"; a known error adds the non-empty details of the
+// outermost Proxy: "id", "timestamp", "domain", "ops", "status", "categories",
+// "tags", "hint", "help_url", "retry_after" and "cause" (or "causes" for
+// several causes). The ID, domain and ops are collected from the whole chain.
+// Returns nil for a nil error:
+//
+//	logger.Error(err, "request failed", knownerror.KeyValues(err)...)
+func KeyValues(err error) []any {
+	if err == nil {
+		return nil
+	}
+	kv := []any{"message", Sanitize(err.Error())}
+	p, ok := AsProxy(err)
+	if !ok {
+		return kv
+	}
+	if id := IDOf(err); id != "" {
+		kv = append(kv, "id", id)
+	}
+	m := p.md()
+	if !m.created.IsZero() {
+		kv = append(kv, "timestamp", m.created)
+	}
+	if domain := DomainOf(err); domain != "" {
+		kv = append(kv, "domain", domain)
+	}
+	if ops := Ops(err); len(ops) > 0 {
+		kv = append(kv, "ops", ops)
+	}
+	if status := StatusCode(err); status != 0 {
+		kv = append(kv, "status", status)
+	}
+	if exts := p.extendsList(); len(exts) > 0 {
+		categories := make([]string, len(exts))
+		for i, ext := range exts {
+			categories[i] = Sanitize(ext.Error())
+		}
+		kv = append(kv, "categories", categories)
+	}
+	if len(m.tags) > 0 {
+		kv = append(kv, "tags", p.Tags())
+	}
+	if m.hint != "" {
+		kv = append(kv, "hint", m.hint)
+	}
+	if m.helpURL != "" {
+		kv = append(kv, "help_url", m.helpURL)
+	}
+	if m.retry > 0 {
+		kv = append(kv, "retry_after", m.retry.String())
+	}
+	if causes := p.Causes(); len(causes) > 1 {
+		msgs := make([]string, len(causes))
+		for i, cause := range causes {
+			msgs[i] = Sanitize(cause.Error())
+		}
+		kv = append(kv, "causes", msgs)
+	} else if p.cause != nil {
+		kv = append(kv, "cause", Sanitize(p.cause.Error()))
+	}
+	return kv
+}
+
+// NewSlogHandler wraps next so that error-valued attributes holding a known
+// error are expanded into a group of KeyValues, without changing log call sites:
+//
+//	logger := slog.New(knownerror.NewSlogHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//	logger.Error("request failed", "err", err)
+//	// {"msg":"request failed","err":{"message":"user not found","status":404,...}}
+//
+// Attributes holding other errors are passed through unchanged.
+func NewSlogHandler(next slog.Handler) slog.Handler {
+	return &slogHandler{next: next}
+}
+
+type slogHandler struct {
+	next slog.Handler
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(expandAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandAttr(a)
+	}
+	return &slogHandler{next: h.next.WithAttrs(expanded)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{next: h.next.WithGroup(name)}
+}
+
+// expandAttr replaces a known error value with a group of its KeyValues,
+// descending into groups.
+func expandAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = expandAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok && IsKnown(err) {
+			return slog.Group(a.Key, KeyValues(err)...)
+		}
+	}
+	return a
+}
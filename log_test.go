@@ -0,0 +1,101 @@
+package knownerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyValues(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").Extends(ErrNotFound).WithTags("some-tag").WithHint("some hint").WithHelpURL("https://example.com/some-help").WithRetryAfter(time.Minute)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := fmt.Errorf("some context: %w", sentinel.WithDomain("some-domain").WithCause(errors.New("some cause")).WithID("some-id").WithOp("some.Op").Clone(Timestamp(created)))
+
+	require.Equal(t, []any{
+		"message", "some context: some error",
+		"id", "some-id",
+		"timestamp", created,
+		"domain", "some-domain",
+		"ops", []string{"some.Op"},
+		"status", 404,
+		"categories", []string{"not found"},
+		"tags", []string{"some-tag"},
+		"hint", "some hint",
+		"help_url", "https://example.com/some-help",
+		"retry_after", "1m0s",
+		"cause", "some cause",
+	}, KeyValues(err))
+}
+
+func TestKeyValues__causes(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").WithCauses(errors.New("some first cause"), errors.New("some second cause"))
+
+	require.Equal(t, []any{
+		"message", "some error",
+		"causes", []string{"some first cause", "some second cause"},
+	}, KeyValues(err))
+}
+
+func TestKeyValues__inner_id(t *testing.T) {
+	t.Parallel()
+
+	err := Wrap(New("some error").WithID("some-id")).WithOp("some.Op")
+
+	require.Equal(t, []any{
+		"message", "some error",
+		"id", "some-id",
+		"ops", []string{"some.Op"},
+	}, KeyValues(err))
+}
+
+func TestKeyValues__unknown(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []any{"message", "some error"}, KeyValues(errors.New("some error")))
+	require.Nil(t, KeyValues(nil))
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	known := New("some error").Extends(ErrNotFound).WithCause(errors.New("some cause"))
+	logger.With("base", known).WithGroup("req").Error("some message",
+		"err", known,
+		"other", errors.New("some other error"),
+		slog.Group("nested", "err", known.WithID("some-id")),
+	)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	expanded := map[string]any{
+		"message":    "some error",
+		"status":     float64(404),
+		"categories": []any{"not found"},
+		"cause":      "some cause",
+	}
+	require.Equal(t, expanded, got["base"])
+	req := got["req"].(map[string]any)
+	require.Equal(t, expanded, req["err"])
+	require.Equal(t, "some other error", req["other"])
+	require.Equal(t, "some-id", req["nested"].(map[string]any)["err"].(map[string]any)["id"])
+}
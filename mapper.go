@@ -0,0 +1,124 @@
+package knownerror
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Mapping is what an error category maps to. Zero fields are left unmapped.
+type Mapping struct {
+	HTTPStatus int
+	ExitCode   int
+}
+
+// Mapper maps errors to HTTP statuses and exit codes by category. Categories
+// registered later take precedence, so deployments can override defaults
+// without forking integrations. The zero value is ready to use and is safe for
+// concurrent use:
+//
+//	m := knownerror.NewMapper()
+//	m.Map(ErrQuotaExceeded, knownerror.Mapping{HTTPStatus: http.StatusTooManyRequests, ExitCode: 75})
+//	m.HTTPStatus(err) // 429 if errors.Is(err, ErrQuotaExceeded)
+type Mapper struct {
+	mu    sync.RWMutex
+	rules []mappingRule
+}
+
+type mappingRule struct {
+	category error
+	mapping  Mapping
+}
+
+// DefaultMapper is the Mapper used by the package's integrations. It maps
+// ErrBadRequest (usage errors) to exit code 2, ErrNotFound to 4, and ErrTimeout,
+// ErrTooManyRequests and ErrServiceUnavailable to 75 (temporary failure).
+var DefaultMapper = newDefaultMapper()
+
+func newDefaultMapper() *Mapper {
+	m := NewMapper()
+	m.Map(ErrBadRequest, Mapping{ExitCode: 2})
+	m.Map(ErrNotFound, Mapping{ExitCode: 4})
+	for _, err := range []error{ErrTimeout, ErrTooManyRequests, ErrServiceUnavailable} {
+		m.Map(err, Mapping{ExitCode: 75})
+	}
+	return m
+}
+
+// ExitCodeOf returns the process exit code for err: the code set via
+// WithExitCode on the outermost Proxy that has one, otherwise
+// DefaultMapper.ExitCode(err). Returns 0 for a nil error:
+//
+//	func main() {
+//		if err := run(); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(knownerror.ExitCodeOf(err))
+//		}
+//	}
+func ExitCodeOf(err error) int {
+	var code int
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && code == 0 {
+			code = p.ExitCode()
+		}
+	})
+	if code != 0 {
+		return code
+	}
+	return DefaultMapper.ExitCode(err)
+}
+
+// NewMapper creates an empty Mapper.
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// Map registers mapping for errors matching category via errors.Is.
+func (m *Mapper) Map(category error, mapping Mapping) {
+	if category == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, mappingRule{category: category, mapping: mapping})
+}
+
+// HTTPStatus returns the HTTP status for err: the most recently registered
+// matching mapping, then StatusCode, then 500. Returns 200 for a nil error.
+func (m *Mapper) HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if status := m.lookup(err, func(mp Mapping) int { return mp.HTTPStatus }); status != 0 {
+		return status
+	}
+	if status := StatusCode(err); status != 0 {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ExitCode returns the process exit code for err: the most recently registered
+// matching mapping, then 1. Returns 0 for a nil error.
+func (m *Mapper) ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code := m.lookup(err, func(mp Mapping) int { return mp.ExitCode }); code != 0 {
+		return code
+	}
+	return 1
+}
+
+// lookup returns the first non-zero field of the matching rules, newest first.
+func (m *Mapper) lookup(err error, field func(Mapping) int) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		r := m.rules[i]
+		if v := field(r.mapping); v != 0 && errors.Is(err, r.category) {
+			return v
+		}
+	}
+	return 0
+}
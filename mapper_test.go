@@ -0,0 +1,58 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapper_HTTPStatus(t *testing.T) {
+	t.Parallel()
+
+	errQuota := errors.New("some quota error")
+	errUserNotFound := New("some user not found").Extends(ErrNotFound)
+
+	m := NewMapper()
+	m.Map(errQuota, Mapping{HTTPStatus: http.StatusTooManyRequests})
+
+	require.Equal(t, http.StatusTooManyRequests, m.HTTPStatus(fmt.Errorf("some context: %w", errQuota)))
+	require.Equal(t, http.StatusNotFound, m.HTTPStatus(errUserNotFound))
+	require.Equal(t, http.StatusInternalServerError, m.HTTPStatus(errors.New("some error")))
+	require.Equal(t, http.StatusOK, m.HTTPStatus(nil))
+
+	m.Map(ErrNotFound, Mapping{HTTPStatus: http.StatusGone})
+	require.Equal(t, http.StatusGone, m.HTTPStatus(errUserNotFound))
+}
+
+func TestMapper_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	errUsage := errors.New("some usage error")
+
+	var m Mapper
+	m.Map(errUsage, Mapping{ExitCode: 2})
+	m.Map(errUsage, Mapping{HTTPStatus: http.StatusBadRequest})
+	m.Map(nil, Mapping{ExitCode: 3})
+
+	require.Equal(t, 2, m.ExitCode(errUsage))
+	require.Equal(t, http.StatusBadRequest, m.HTTPStatus(errUsage))
+	require.Equal(t, 1, m.ExitCode(errors.New("some error")))
+	require.Zero(t, m.ExitCode(nil))
+}
+
+func TestExitCodeOf(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithExitCode(3)
+
+	require.Equal(t, 3, ExitCodeOf(fmt.Errorf("some context: %w", sentinel.WithCause(errors.New("some cause")))))
+	require.Equal(t, 5, ExitCodeOf(Wrap(sentinel).WithExitCode(5)))
+	require.Equal(t, 2, ExitCodeOf(ErrInvalid))
+	require.Equal(t, 4, ExitCodeOf(New("some error").Extends(ErrNotFound)))
+	require.Equal(t, 75, ExitCodeOf(ErrTimeout.WithCause(errors.New("some cause"))))
+	require.Equal(t, 1, ExitCodeOf(errors.New("some error")))
+	require.Zero(t, ExitCodeOf(nil))
+}
@@ -0,0 +1,59 @@
+package knownerror
+
+import "errors"
+
+// Matcher is a predicate over errors. Matchers compose with AnyOf, AllOf and Not:
+//
+//	if knownerror.Match(err, knownerror.AnyOf(
+//		knownerror.Category(ErrNotFound),
+//		knownerror.Category(ErrGone),
+//	)) {
+//		return http.StatusNotFound
+//	}
+type Matcher func(err error) bool
+
+// Match reports whether err satisfies m. A nil err never matches.
+func Match(err error, m Matcher) bool {
+	if err == nil || m == nil {
+		return false
+	}
+	return m(err)
+}
+
+// Category matches errors for which errors.Is(err, target) is true.
+func Category(target error) Matcher {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// AnyOf matches if at least one of ms matches.
+func AnyOf(ms ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range ms {
+			if Match(err, m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf matches if every one of ms matches. An empty AllOf matches any non-nil error.
+func AllOf(ms ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range ms {
+			if !Match(err, m) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not inverts m.
+func Not(m Matcher) Matcher {
+	return func(err error) bool {
+		return !Match(err, m)
+	}
+}
@@ -0,0 +1,108 @@
+package knownerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("some not found")
+	errGone := errors.New("some gone")
+	errForbidden := errors.New("some forbidden")
+	err := New("some error").Extends(errNotFound, errForbidden)
+
+	tests := []struct {
+		name string
+		got  struct {
+			matcher Matcher
+		}
+		want struct {
+			matched bool
+		}
+	}{
+		{
+			name: "category",
+			got: struct {
+				matcher Matcher
+			}{
+				matcher: Category(errNotFound),
+			},
+			want: struct {
+				matched bool
+			}{
+				matched: true,
+			},
+		},
+		{
+			name: "category_not_extended",
+			got: struct {
+				matcher Matcher
+			}{
+				matcher: Category(errGone),
+			},
+			want: struct {
+				matched bool
+			}{
+				matched: false,
+			},
+		},
+		{
+			name: "any_of",
+			got: struct {
+				matcher Matcher
+			}{
+				matcher: AnyOf(Category(errGone), Category(errNotFound)),
+			},
+			want: struct {
+				matched bool
+			}{
+				matched: true,
+			},
+		},
+		{
+			name: "all_of",
+			got: struct {
+				matcher Matcher
+			}{
+				matcher: AllOf(Category(errNotFound), Category(errGone)),
+			},
+			want: struct {
+				matched bool
+			}{
+				matched: false,
+			},
+		},
+		{
+			name: "not",
+			got: struct {
+				matcher Matcher
+			}{
+				matcher: AllOf(Category(errForbidden), Not(Category(errGone))),
+			},
+			want: struct {
+				matched bool
+			}{
+				matched: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want.matched, Match(err, tt.got.matcher))
+		})
+	}
+}
+
+func TestMatch__nil(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Match(nil, Not(Category(errors.New("some error")))))
+	require.False(t, Match(New("some error"), nil))
+}
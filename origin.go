@@ -0,0 +1,53 @@
+package knownerror
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+var originEnabled atomic.Bool
+
+// CaptureOrigin enables or disables recording where errors are raised. While
+// enabled, New, Newf, Wrap, Compose, Builder.Build, WithCause and WithCauses
+// record their caller with a single runtime.Callers call, a cheap alternative
+// to full stack traces. The origin is reported by Origin and printed by %+v:
+//
+//	knownerror.CaptureOrigin(true)
+//	err := ErrUserNotFound.WithCause(sql.ErrNoRows)
+//	fmt.Printf("%+v", err) // user not found (origin: /src/user/repo.go:42, cause: sql: no rows in result set)
+//
+// Errors created while disabled, such as package-level sentinels declared
+// before it is enabled, have no origin.
+func CaptureOrigin(enabled bool) {
+	originEnabled.Store(enabled)
+}
+
+// Origin returns the call site recorded while CaptureOrigin was enabled, or a
+// zero Frame.
+func (e *Proxy) Origin() runtime.Frame {
+	pc := e.md().origin
+	if pc == 0 {
+		return runtime.Frame{}
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return f
+}
+
+// create records the origin of a new Proxy and calls the OnCreate hooks, with
+// the call site skip frames above the caller of create. Returns p.
+func create(p *Proxy, skip int) *Proxy {
+	capture(p, skip+1)
+	return runHooks(&hooks.create, p, skip+1)
+}
+
+// capture records the call site skip frames above the caller of capture as the
+// origin of p, if CaptureOrigin is enabled.
+func capture(p *Proxy, skip int) {
+	if !originEnabled.Load() {
+		return
+	}
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) > 0 {
+		p.setMeta().origin = pcs[0]
+	}
+}
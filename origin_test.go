@@ -0,0 +1,41 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureOrigin(t *testing.T) {
+	CaptureOrigin(true)
+	t.Cleanup(func() { CaptureOrigin(false) })
+
+	sentinel := New("some error")
+	err := sentinel.WithCause(errors.New("some cause"))
+	wrapped := wrapHelper(errors.New("some error"))
+	cloned := sentinel.Clone(CausedBy(errors.New("some cause")))
+
+	for _, p := range []*Proxy{sentinel, err, wrapped, cloned} {
+		origin := p.Origin()
+		require.Equal(t, "github.com/pprishchepa/knownerror.TestCaptureOrigin", origin.Function)
+		require.Contains(t, origin.File, "origin_test.go")
+	}
+	require.NotEqual(t, sentinel.Origin().Line, err.Origin().Line)
+	require.NotEqual(t, sentinel.Origin().Line, cloned.Origin().Line)
+
+	origin := err.Origin()
+	want := "some error (origin: " + origin.File + ":" + strconv.Itoa(origin.Line) + ", cause: some cause)"
+	require.Equal(t, want, fmt.Sprintf("%+v", err))
+}
+
+func TestCaptureOrigin__disabled(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").WithCause(errors.New("some cause"))
+
+	require.Zero(t, err.Origin())
+	require.Equal(t, "some error (cause: some cause)", fmt.Sprintf("%+v", err))
+}
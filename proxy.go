@@ -3,8 +3,19 @@
 package knownerror
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Proxy wraps an error, allows it to match multiple sentinel errors via Is/As,
@@ -12,17 +23,183 @@ import (
 type Proxy struct {
 	base    error
 	cause   error
-	extends []error
+	parent  *Proxy
+	extends *chain
+	op      string
+	meta    *meta
+
+	transparentCause bool
+	implicitCause    bool // the cause came from the %w operands of Newf
+	timeout          flag
+	temporary        flag
+	causeMessage     flag
+}
+
+// meta holds the rarely set details of a Proxy. Derived copies share it, so
+// deriving stays small; setters attach an updated copy instead of changing it.
+type meta struct {
+	format  string
+	inline  string
+	without []error
+	id      string
+	created time.Time
+	helpURL string
+	hint    string
+	public  bool
+	tags    []string
+	domain  string
+	retry   time.Duration
+	quota   *QuotaDetail
+	status  int
+	exit    int
+	origin  uintptr
+}
+
+var noMeta meta
+
+// md returns the details of e for reading.
+func (e *Proxy) md() *meta {
+	if e.meta != nil {
+		return e.meta
+	}
+	return &noMeta
+}
+
+// setMeta attaches a copy of e's details to e and returns it for writing. Only
+// call it on a Proxy that is not published yet.
+func (e *Proxy) setMeta() *meta {
+	m := new(meta)
+	if e.meta != nil {
+		*m = *e.meta
+	}
+	e.meta = m
+	return m
+}
+
+// setCause replaces the cause, forgetting what Newf derived from its %w
+// operands: the formatted message and their visibility to errors.Is.
+func (e *Proxy) setCause(cause error) {
+	e.cause = cause
+	e.implicitCause = false
+	if e.md().inline != "" {
+		e.setMeta().inline = ""
+	}
+}
+
+// causeVisible reports whether errors.Is and errors.As see the cause.
+func (e *Proxy) causeVisible() bool {
+	return e.transparentCause || e.implicitCause
+}
+
+// flag is a boolean that can be left unset.
+type flag uint8
+
+const (
+	flagUnset flag = iota
+	flagTrue
+	flagFalse
+)
+
+func flagOf(v bool) flag {
+	if v {
+		return flagTrue
+	}
+	return flagFalse
 }
 
 // New creates a Proxy with a simple text message.
 func New(text string) *Proxy {
-	return &Proxy{base: errors.New(text)}
+	return create(&Proxy{base: errors.New(text)}, 1)
+}
+
+// NewSkip is like New, but reports the call site skip frames further up the
+// stack to hooks. See WrapSkip.
+func NewSkip(text string, skip int) *Proxy {
+	return create(&Proxy{base: errors.New(text)}, 1+skip)
 }
 
-// Newf creates a Proxy with a formatted message.
+// Newf creates a Proxy with a formatted message. Errors formatted with %w
+// become the cause, visible to errors.Is and errors.As:
+//
+//	err := knownerror.Newf("loading user: %w", sql.ErrNoRows)
+//	err.Error()                   // "loading user: sql: no rows in result set"
+//	err.Message()                 // "loading user"
+//	err.Cause()                   // sql.ErrNoRows
+//	errors.Is(err, sql.ErrNoRows) // true
+//
+// Error keeps the formatted message as is, while Message drops the %w operands
+// so renderers can show it without the cause.
 func Newf(format string, args ...any) *Proxy {
-	return &Proxy{base: fmt.Errorf(format, args...)}
+	base := fmt.Errorf(format, args...)
+	p := &Proxy{base: base, meta: &meta{format: format}}
+	switch x := base.(type) {
+	case interface{ Unwrap() error }:
+		p.cause = x.Unwrap()
+	case interface{ Unwrap() []error }:
+		if causes := nonNil(x.Unwrap()); len(causes) == 1 {
+			p.cause = causes[0]
+		} else if len(causes) > 1 {
+			p.cause = &multiCause{errs: causes}
+		}
+	}
+	if p.cause != nil {
+		// The message already includes the cause.
+		p.meta.inline = base.Error()
+		p.base = errors.New(fmt.Errorf(stripWrapped(format), args...).Error())
+		p.implicitCause = true
+	}
+	return create(p, 1)
+}
+
+// stripWrapped rewrites the %w verbs of format to print nothing, keeping the
+// argument numbering intact. The separators in front of them go too, or behind
+// them when nothing precedes, and so do parentheses around them. Only literal
+// text is trimmed, never the formatted operands.
+func stripWrapped(format string) string {
+	var b strings.Builder
+	leading := true
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			leading = false
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[j]) >= 0 {
+			j++
+		}
+		if j == len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+		spec := format[i+1 : j]
+		if format[j] != 'w' || strings.Contains(spec, "*") {
+			b.WriteString(format[i : j+1])
+			leading = false
+			i = j
+			continue
+		}
+		prefix := b.String()
+		if strings.HasSuffix(prefix, "(") && strings.HasPrefix(format[j+1:], ")") {
+			prefix = prefix[:len(prefix)-1]
+			j++
+		}
+		prefix = strings.TrimRight(prefix, " :;,")
+		b.Reset()
+		b.WriteString(prefix)
+		b.WriteString("%.0")
+		if k := strings.IndexByte(spec, '['); k >= 0 {
+			b.WriteString(spec[k:])
+		}
+		b.WriteByte('w')
+		if leading {
+			for j+1 < len(format) && strings.IndexByte(" :;,", format[j+1]) >= 0 {
+				j++
+			}
+		}
+		i = j
+	}
+	return b.String()
 }
 
 // Wrap converts an existing error into a Proxy. Returns nil if err is nil.
@@ -30,7 +207,20 @@ func Wrap(err error) *Proxy {
 	if err == nil {
 		return nil
 	}
-	return &Proxy{base: err}
+	return create(&Proxy{base: err}, 1)
+}
+
+// WrapSkip is like Wrap, but reports the call site skip frames further up the
+// stack to hooks, so thin wrappers around knownerror point at their callers:
+//
+//	func wrapDB(err error) *knownerror.Proxy {
+//		return knownerror.WrapSkip(err, 1).Extends(ErrDatabase)
+//	}
+func WrapSkip(err error, skip int) *Proxy {
+	if err == nil {
+		return nil
+	}
+	return create(&Proxy{base: err}, 1+skip)
 }
 
 // WithCause attaches a root cause error and preserves the original error identity:
@@ -43,12 +233,65 @@ func (e *Proxy) WithCause(cause error) *Proxy {
 	if cause == nil {
 		return e
 	}
-	cpy := *e
-	cpy.cause = cause
-	cpy.extends = make([]error, 0, len(e.extends)+1)
-	cpy.extends = append(cpy.extends, e)
-	cpy.extends = append(cpy.extends, e.extends...)
-	return &cpy
+	cpy := e.derive()
+	cpy.setCause(cause)
+	capture(cpy, 1)
+	return runHooks(&hooks.wrap, cpy, 1)
+}
+
+// WithCauses attaches several independent root causes, e.g. when every replica
+// failed. Cause returns them joined, Causes returns them separately:
+//
+//	err := ErrUnavailable.WithCauses(errReplica1, errReplica2)
+//	fmt.Printf("%+v", err) // unavailable (cause: replica 1 down; replica 2 down)
+//	err.Causes()           // [errReplica1, errReplica2]
+//
+// Nil causes are ignored.
+func (e *Proxy) WithCauses(causes ...error) *Proxy {
+	causes = nonNil(causes)
+	if len(causes) == 0 {
+		return e
+	}
+	cpy := e.derive()
+	if len(causes) == 1 {
+		cpy.setCause(causes[0])
+	} else {
+		cpy.setCause(&multiCause{errs: causes})
+	}
+	capture(cpy, 1)
+	return runHooks(&hooks.wrap, cpy, 1)
+}
+
+// multiCause holds the causes attached via WithCauses.
+type multiCause struct {
+	errs []error
+}
+
+func (m *multiCause) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (m *multiCause) Unwrap() []error {
+	return m.errs
+}
+
+// WithOp records the logical operation that produced the error and preserves
+// the original error identity. Use Ops to collect operations across wraps:
+//
+//	err := ErrUserNotFound.WithCause(sql.ErrNoRows).WithOp("userrepo.Get")
+//	errors.Is(err, ErrUserNotFound) // true
+//	err.Op()                        // "userrepo.Get"
+func (e *Proxy) WithOp(op string) *Proxy {
+	if op == "" {
+		return e
+	}
+	cpy := e.derive()
+	cpy.op = op
+	return cpy
 }
 
 // Extends adds error categories. The Proxy will match all extended errors via errors.Is:
@@ -56,79 +299,687 @@ func (e *Proxy) WithCause(cause error) *Proxy {
 //	var ErrNotFound = errors.New("not found")
 //	var ErrUserNotFound = knownerror.New("user not found").Extends(ErrNotFound)
 //	errors.Is(ErrUserNotFound, ErrNotFound) // true
+//
+// Declaration order is preserved across calls: As returns the first match in
+// that order and ExtendsList reports it.
 func (e *Proxy) Extends(errs ...error) *Proxy {
-	nonNilErrs := make([]error, 0, len(errs))
-	for _, err := range errs {
-		if err != nil {
-			nonNilErrs = append(nonNilErrs, err)
-		}
+	nonNilErrs := nonNil(errs)
+	if len(nonNilErrs) == 0 {
+		return e
 	}
+	// Allocate the copy and its chain node together.
+	cpy := &struct {
+		Proxy
+		node chain
+	}{Proxy: *e, node: chain{prev: e.extends, errs: nonNilErrs, size: e.extends.len() + len(nonNilErrs)}}
+	cpy.extends = &cpy.node
+	cpy.restore(nonNilErrs)
+	return runHooks(&hooks.wrap, &cpy.Proxy, 1)
+}
+
+// restore undoes WithoutCategory for errs: extending with an excluded
+// category restores it.
+func (e *Proxy) restore(errs []error) {
+	without := e.md().without
+	if len(without) == 0 {
+		return
+	}
+	e.setMeta().without = slices.DeleteFunc(slices.Clone(without), func(w error) bool {
+		return slices.ContainsFunc(errs, func(err error) bool { return sameError(w, err) })
+	})
+}
+
+// WithoutCategory returns a copy that no longer matches errs via errors.Is and
+// errors.As, overriding the categorization of a lower layer:
+//
+//	err := repoErr.WithoutCategory(ErrRetryable)
+//	errors.Is(err, ErrRetryable) // false
+//
+// Only matches through extended errors, including those of the error it was
+// derived from, are removed. Errors reachable by unwrapping the base error
+// still match, so knownerror.Wrap(repoErr).WithoutCategory(ErrRetryable) still
+// matches ErrRetryable through repoErr. Extending the copy with an excluded
+// error restores it.
+func (e *Proxy) WithoutCategory(errs ...error) *Proxy {
+	nonNilErrs := nonNil(errs)
 	if len(nonNilErrs) == 0 {
 		return e
 	}
+	cpy := e.derive()
+	cpy.setMeta().without = append(slices.Clip(e.md().without), nonNilErrs...)
+	return cpy
+}
+
+// excludes reports whether err was removed via WithoutCategory.
+func (e *Proxy) excludes(err error) bool {
+	return slices.ContainsFunc(e.md().without, func(w error) bool { return sameError(w, err) })
+}
+
+// sameError reports whether a and b are the same comparable error.
+func sameError(a, b error) bool {
+	return reflect.TypeOf(b).Comparable() && a == b
+}
+
+// WithTransparentCause returns a copy whose cause is matched by errors.Is and
+// errors.As, after the extended errors. By default causes are hidden:
+//
+//	err := ErrUserNotFound.WithCause(sql.ErrNoRows)
+//	errors.Is(err, sql.ErrNoRows)                         // false
+//	errors.Is(err.WithTransparentCause(), sql.ErrNoRows) // true
+func (e *Proxy) WithTransparentCause() *Proxy {
+	if e.transparentCause {
+		return e
+	}
+	cpy := e.derive()
+	cpy.transparentCause = true
+	return cpy
+}
+
+// WithTimeout returns a copy that errors.As extracts as a net.Error whose
+// Timeout method reports timeout, so it passes through retry logic that
+// type-asserts on net.Error:
+//
+//	var netErr net.Error
+//	errors.As(ErrUpstream.WithTimeout(true), &netErr) // true, netErr.Timeout() is true
+//
+// Proxies without WithTimeout or WithTemporary are not net.Errors. The
+// original error identity is preserved.
+func (e *Proxy) WithTimeout(timeout bool) *Proxy {
+	cpy := e.derive()
+	cpy.timeout = flagOf(timeout)
+	return cpy
+}
+
+// WithTemporary returns a copy that errors.As extracts as a net.Error whose
+// Temporary method reports temporary, like WithTimeout. The original error
+// identity is preserved.
+func (e *Proxy) WithTemporary(temporary bool) *Proxy {
+	cpy := e.derive()
+	cpy.temporary = flagOf(temporary)
+	return cpy
+}
+
+// WithID attaches an instance ID that can be returned to clients and searched
+// in logs. The original error identity is preserved.
+func (e *Proxy) WithID(id string) *Proxy {
+	if id == "" {
+		return e
+	}
+	cpy := e.derive()
+	cpy.setMeta().id = id
+	return cpy
+}
+
+// WithNewID attaches a randomly generated instance ID:
+//
+//	err := ErrPaymentFailed.WithCause(err).WithNewID()
+//	fmt.Printf("reference: %s", err.ID()) // reference: 7f3a9c0e5b21d4a8
+func (e *Proxy) WithNewID() *Proxy {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return e.WithID(hex.EncodeToString(b[:]))
+}
+
+// WithHelpURL attaches a link to a runbook or documentation page for the error.
+// The original error identity is preserved.
+func (e *Proxy) WithHelpURL(url string) *Proxy {
+	if url == "" {
+		return e
+	}
+	cpy := e.derive()
+	cpy.setMeta().helpURL = url
+	return cpy
+}
+
+// WithHint attaches a remediation hint telling the reader what to do about the
+// error. The hint is printed by %+v. The original error identity is preserved:
+//
+//	var ErrNoBillingScope = knownerror.New("permission denied").
+//		WithHint("check that the API key has the billing scope")
+func (e *Proxy) WithHint(hint string) *Proxy {
+	if hint == "" {
+		return e
+	}
+	cpy := e.derive()
+	m := cpy.setMeta()
+	m.hint, m.public = hint, false
+	return cpy
+}
+
+// WithPublicHint is like WithHint, but marks the hint as safe to show to
+// clients, e.g. in khttp responses. Plain hints stay internal:
+//
+//	var ErrNoBillingScope = knownerror.New("permission denied").
+//		WithPublicHint("ask your administrator for the billing scope")
+func (e *Proxy) WithPublicHint(hint string) *Proxy {
+	if hint == "" {
+		return e
+	}
+	cpy := e.derive()
+	m := cpy.setMeta()
+	m.hint, m.public = hint, true
+	return cpy
+}
+
+// WithTags returns a copy labeled with cross-cutting tags, added to the tags
+// the error already has. Tags route and filter errors (alert channels,
+// dashboards) without being full categories:
+//
+//	var ErrChargeFailed = knownerror.New("charge failed").WithTags("billing", "external")
+//	knownerror.HasTag(err, "billing") // true
+func (e *Proxy) WithTags(tags ...string) *Proxy {
+	cpy := e.derive()
+	cpy.setMeta().tags = appendTags(slices.Clip(e.md().tags), tags)
+	return cpy
+}
+
+func appendTags(dst []string, tags []string) []string {
+	for _, tag := range tags {
+		if tag != "" && !slices.Contains(dst, tag) {
+			dst = append(dst, tag)
+		}
+	}
+	return dst
+}
+
+// WithDomain returns a copy attributed to the owning subsystem, so errors in a
+// multi-module application can be traced back to their owners:
+//
+//	var ErrChargeFailed = knownerror.New("charge failed").WithDomain("payments")
+func (e *Proxy) WithDomain(domain string) *Proxy {
+	cpy := e.derive()
+	cpy.setMeta().domain = domain
+	return cpy
+}
+
+// WithRetryAfter returns a copy telling the client how long to wait before
+// retrying, e.g. for rate limiting or maintenance. HTTP integrations report it
+// in the Retry-After header:
+//
+//	err := ErrTooManyRequests.WithRetryAfter(30 * time.Second)
+func (e *Proxy) WithRetryAfter(d time.Duration) *Proxy {
+	cpy := e.derive()
+	cpy.setMeta().retry = d
+	return cpy
+}
+
+// WithExitCode sets the process exit code reported by ExitCodeOf, for
+// command-line tools. The original error identity is preserved.
+func (e *Proxy) WithExitCode(code int) *Proxy {
+	cpy := e.derive()
+	cpy.setMeta().exit = code
+	return cpy
+}
+
+// WithTimestamp records the current time on a copy of the error. Useful when
+// errors are buffered, retried or transported and the failure time matters.
+func (e *Proxy) WithTimestamp() *Proxy {
+	cpy := e.derive()
+	cpy.setMeta().created = time.Now()
+	return cpy
+}
+
+// Clone returns a copy of the error with opts applied, making per-request
+// derivations of shared sentinels explicit. The copy still matches e via
+// errors.Is, even if its message is overridden:
+//
+//	err := ErrQuotaExceeded.Clone(
+//		knownerror.Message("quota of 100 requests exceeded"),
+//		knownerror.Hint("retry after midnight UTC"),
+//	)
+//	errors.Is(err, ErrQuotaExceeded) // true
+//
+// Like WithCause and Extends, a new cause records the origin, and a new cause
+// or category runs the OnWrap hooks. Proxies are immutable, so the copy shares
+// nothing that can change under it.
+func (e *Proxy) Clone(opts ...Option) *Proxy {
+	cpy := e.derive()
+	for _, opt := range opts {
+		opt(cpy)
+	}
+	caused := cpy.cause != nil && (e.cause == nil || !sameError(cpy.cause, e.cause))
+	if caused {
+		capture(cpy, 1)
+	}
+	if caused || cpy.extends != nil {
+		return runHooks(&hooks.wrap, cpy, 1)
+	}
+	return cpy
+}
+
+// derive returns a copy of e that still matches e via errors.Is. The copy
+// links to e instead of copying its extended errors, so deriving is O(1).
+func (e *Proxy) derive() *Proxy {
 	cpy := *e
-	cpy.extends = make([]error, 0, len(e.extends)+len(nonNilErrs))
-	cpy.extends = append(cpy.extends, e.extends...)
-	cpy.extends = append(cpy.extends, nonNilErrs...)
+	cpy.parent = e
+	cpy.extends = nil
 	return &cpy
 }
 
-// Error returns the error message.
+var causeInMessage atomic.Bool
+
+// IncludeCauseInMessage makes Error return "message: cause message", like
+// fmt.Errorf wrapping, for Proxies that do not decide via WithCauseInMessage.
+// Useful when log pipelines only capture err.Error(). Disabled by default.
+func IncludeCauseInMessage(enabled bool) {
+	causeInMessage.Store(enabled)
+}
+
+// WithCauseInMessage returns a copy whose Error includes the cause message, or
+// explicitly does not, regardless of IncludeCauseInMessage:
+//
+//	err := ErrUserNotFound.WithCause(sql.ErrNoRows).WithCauseInMessage(true)
+//	err.Error() // "user not found: sql: no rows in result set"
+func (e *Proxy) WithCauseInMessage(enabled bool) *Proxy {
+	cpy := e.derive()
+	cpy.causeMessage = flagOf(enabled)
+	return cpy
+}
+
+// Error returns the error message. The cause message is appended if enabled
+// via WithCauseInMessage or IncludeCauseInMessage, unless it is already part of
+// the message formatted by Newf. Wrapping a Proxy keeps its Error as is.
 func (e *Proxy) Error() string {
+	if inline := e.md().inline; inline != "" {
+		return Sanitize(inline)
+	}
+	var msg string
+	if base, ok := e.base.(*Proxy); ok && base != nil {
+		msg = base.Error()
+	} else {
+		msg = e.Message()
+	}
+	if e.cause == nil {
+		return msg
+	}
+	if e.causeMessage == flagTrue || e.causeMessage == flagUnset && causeInMessage.Load() {
+		return msg + ": " + Sanitize(e.cause.Error())
+	}
+	return msg
+}
+
+// Message returns the message of the base error, never including the cause.
+// For Newf, that is the message without the %w operands; for a wrapped Proxy,
+// its Message.
+func (e *Proxy) Message() string {
+	if base, ok := e.base.(*Proxy); ok && base != nil {
+		return base.Message()
+	}
 	if e.base != nil {
-		return e.base.Error()
+		return Sanitize(e.base.Error())
 	}
 	return ""
 }
 
+// messageOf returns the Message of a Proxy, or the Error of other errors.
+func messageOf(err error) string {
+	if p, ok := err.(*Proxy); ok && p != nil {
+		return p.Message()
+	}
+	return err.Error()
+}
+
 // Unwrap is a hook for errors.Unwrap. Returns the base error.
 func (e *Proxy) Unwrap() error {
 	return e.base
 }
 
-// Cause returns the root cause error attached via WithCause.
+// Cause returns the root cause error attached via WithCause. Causes attached
+// via WithCauses are returned as one error that unwraps to all of them.
 func (e *Proxy) Cause() error {
 	return e.cause
 }
 
+// Causes returns the root causes attached via WithCause or WithCauses.
+func (e *Proxy) Causes() []error {
+	if m, ok := e.cause.(*multiCause); ok {
+		return slices.Clone(m.errs)
+	}
+	if e.cause != nil {
+		return []error{e.cause}
+	}
+	return nil
+}
+
+// Op returns the operation recorded via WithOp.
+func (e *Proxy) Op() string {
+	return e.op
+}
+
+// ID returns the instance ID set via WithID or WithNewID.
+func (e *Proxy) ID() string {
+	return e.md().id
+}
+
+// HelpURL returns the link set via WithHelpURL.
+func (e *Proxy) HelpURL() string {
+	return e.md().helpURL
+}
+
+// Hint returns the remediation hint set via WithHint or WithPublicHint.
+func (e *Proxy) Hint() string {
+	return e.md().hint
+}
+
+// PublicHint returns the hint set via WithPublicHint, or "" if the hint is not
+// marked public.
+func (e *Proxy) PublicHint() string {
+	if m := e.md(); m.public {
+		return m.hint
+	}
+	return ""
+}
+
+// Tags returns the tags set via WithTags.
+func (e *Proxy) Tags() []string {
+	return slices.Clone(e.md().tags)
+}
+
+// Domain returns the subsystem set via WithDomain.
+func (e *Proxy) Domain() string {
+	return e.md().domain
+}
+
+// RetryAfter returns the delay set via WithRetryAfter, or 0.
+func (e *Proxy) RetryAfter() time.Duration {
+	return e.md().retry
+}
+
+// ExitCode returns the exit code set via WithExitCode, or 0.
+func (e *Proxy) ExitCode() int {
+	return e.md().exit
+}
+
+// Timestamp returns the time recorded via WithTimestamp, or the zero time.
+func (e *Proxy) Timestamp() time.Time {
+	return e.md().created
+}
+
+// netError is what errors.As extracts from a Proxy with WithTimeout or
+// WithTemporary for targets like net.Error. The Proxy itself does not have
+// Timeout and Temporary methods, so others do not pass as net.Errors.
+type netError struct {
+	p *Proxy
+}
+
+var netErrorType = reflect.TypeFor[netError]()
+
+func (n netError) Error() string {
+	return n.p.Error()
+}
+
+func (n netError) Unwrap() error {
+	return n.p
+}
+
+// Timeout returns the value set via WithTimeout; if unset, reports whether the
+// base error is a timeout or the Proxy extends ErrTimeout or
+// context.DeadlineExceeded.
+func (n netError) Timeout() bool {
+	switch n.p.timeout {
+	case flagTrue:
+		return true
+	case flagFalse:
+		return false
+	}
+	return IsTimeout(n.p.base) || n.p.Is(ErrTimeout) || n.p.Is(context.DeadlineExceeded)
+}
+
+// Temporary returns the value set via WithTemporary; if unset, reports the base
+// error's Temporary result, if any.
+func (n netError) Temporary() bool {
+	switch n.p.temporary {
+	case flagTrue:
+		return true
+	case flagFalse:
+		return false
+	}
+	var t interface{ Temporary() bool }
+	return errors.As(n.p.base, &t) && t.Temporary()
+}
+
+// asNetError sets target to a netError for e if e has WithTimeout or
+// WithTemporary and target wants a type only the netError satisfies.
+func (e *Proxy) asNetError(target any) bool {
+	if e.timeout == flagUnset && e.temporary == flagUnset {
+		return false
+	}
+	val := reflect.ValueOf(target)
+	targetType := val.Type().Elem()
+	if !netErrorType.AssignableTo(targetType) || reflect.TypeOf(e).AssignableTo(targetType) {
+		return false
+	}
+	val.Elem().Set(reflect.ValueOf(netError{p: e}))
+	return true
+}
+
 // Is is a hook for errors.Is. Reports whether any extended error matches target.
+// Traversal is depth-limited, so cycles through mutable third-party errors
+// terminate instead of recursing forever.
 func (e *Proxy) Is(target error) bool {
 	if target == nil {
 		return false
 	}
-	for _, ext := range e.extends {
-		if errors.Is(ext, target) {
-			return true
-		}
-	}
-	return false
+	return e.extendsIs(target, 0)
 }
 
 // As is a hook for errors.As. Finds the first extended error that matches target.
+//
+// errors.As checks the Proxy itself before calling this hook, so extracting a
+// *Proxy from a chain built with fmt.Errorf("%w") or errors.Join works, and the
+// outermost Proxy wins:
+//
+//	var p *knownerror.Proxy
+//	errors.As(fmt.Errorf("loading user: %w", err), &p) // p is err
 func (e *Proxy) As(target any) bool {
-	for _, ext := range e.extends {
-		if errors.As(ext, target) {
-			return true
+	return e.extendsAs(target, 0)
+}
+
+// extendsIs and extendsAs do not count steps to the parent toward maxDepth:
+// parents are immutable and older than their children, so they cannot form a
+// cycle, and long WithOp chains must keep matching the original error.
+func (e *Proxy) extendsIs(target error, depth int) bool {
+	if len(e.md().without) > 0 && e.excludes(target) {
+		return false
+	}
+	if e.parent != nil && is(e.parent, target, depth) {
+		return true
+	}
+	if e.extends.is(target, depth+1) {
+		return true
+	}
+	return e.causeVisible() && is(e.cause, target, depth+1)
+}
+
+func (e *Proxy) extendsAs(target any, depth int) bool {
+	if e.asNetError(target) {
+		return true
+	}
+	if len(e.md().without) > 0 {
+		for _, ext := range e.extendsList() {
+			if as(ext, target, depth+1) {
+				return true
+			}
 		}
+		return e.causeVisible() && as(e.cause, target, depth+1)
 	}
-	return false
+	if e.parent != nil && as(e.parent, target, depth) {
+		return true
+	}
+	if e.extends.each(func(ext error) bool {
+		return as(ext, target, depth+1)
+	}) {
+		return true
+	}
+	return e.causeVisible() && as(e.cause, target, depth+1)
 }
 
-// Format implements fmt.Formatter. With %+v, prints the error and cause:
+// Format implements fmt.Formatter. With %+v, prints the message followed by its
+// ID, hint, origin and cause:
 //
 //	err := knownerror.New("db error").WithCause(errors.New("connection refused"))
 //	fmt.Printf("%+v", err) // db error (cause: connection refused)
 func (e *Proxy) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
-		if s.Flag('+') && e.cause != nil {
-			_, _ = fmt.Fprintf(s, "%s (cause: %s)", e.Error(), e.cause)
+		if s.Flag('+') {
+			msg, cause := e.summary()
+			_, _ = io.WriteString(s, msg)
+			e.formatDetails(s, cause)
 			return
 		}
 		fallthrough
 	case 's':
-		_, _ = fmt.Fprint(s, e.Error())
+		_, _ = io.WriteString(s, e.Error())
 	case 'q':
-		_, _ = fmt.Fprintf(s, "%q", e.Error())
+		_, _ = io.WriteString(s, strconv.Quote(e.Error()))
+	}
+}
+
+// summary returns the message printed by %+v and the cause to print after it.
+// A wrapped Proxy lends its own, so wrapping does not hide the cause; the
+// message formatted by Newf already holds its causes.
+func (e *Proxy) summary() (string, error) {
+	if inline := e.md().inline; inline != "" {
+		return Sanitize(inline), nil
+	}
+	msg, cause := e.Message(), e.cause
+	if base, ok := e.base.(*Proxy); ok && base != nil {
+		var baseCause error
+		msg, baseCause = base.summary()
+		if cause == nil {
+			cause = baseCause
+		}
+	}
+	return msg, cause
+}
+
+// formatDetails writes " (label: value, ...)" for the non-empty details.
+func (e *Proxy) formatDetails(w io.Writer, err error) {
+	m := e.md()
+	var origin, cause string
+	if m.origin != 0 {
+		f := e.Origin()
+		origin = f.File + ":" + strconv.Itoa(f.Line)
+	}
+	if err != nil {
+		cause = Sanitize(err.Error())
+	}
+	details := [...]struct{ label, value string }{
+		{"id", m.id},
+		{"hint", m.hint},
+		{"origin", origin},
+		{"cause", cause},
+	}
+	sep := " ("
+	for _, d := range details {
+		if d.value == "" {
+			continue
+		}
+		_, _ = io.WriteString(w, sep)
+		_, _ = io.WriteString(w, d.label)
+		_, _ = io.WriteString(w, ": ")
+		_, _ = io.WriteString(w, d.value)
+		sep = ", "
+	}
+	if sep != " (" {
+		_, _ = io.WriteString(w, ")")
+	}
+}
+
+// indexThreshold is the chain length from which is uses an identity index
+// instead of a linear scan.
+const indexThreshold = 8
+
+// chain is an immutable list of extended errors. Each Extends call adds a node
+// pointing to the previous one, so derived Proxies share their ancestors' nodes.
+type chain struct {
+	prev *chain
+	errs []error
+	size int
+
+	once  sync.Once
+	index map[error]struct{} // plain comparable errors, matched by identity
+	rest  []error            // errors that need a full errors.Is check
+}
+
+// nonNil returns a copy of errs without nil errors.
+func nonNil(errs []error) []error {
+	nonNilErrs := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNilErrs = append(nonNilErrs, err)
+		}
+	}
+	return nonNilErrs
+}
+
+func (c *chain) len() int {
+	if c == nil {
+		return 0
+	}
+	return c.size
+}
+
+// is reports whether any error in the chain matches target via errors.Is.
+// Long chains are indexed on first use, so checks against plain sentinel
+// errors take constant time.
+func (c *chain) is(target error, depth int) bool {
+	if c.len() < indexThreshold {
+		return c.each(func(ext error) bool {
+			return is(ext, target, depth)
+		})
 	}
+	c.once.Do(c.buildIndex)
+	if reflect.TypeOf(target).Comparable() {
+		if _, ok := c.index[target]; ok {
+			return true
+		}
+	}
+	for _, ext := range c.rest {
+		if is(ext, target, depth) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *chain) buildIndex() {
+	c.index = make(map[error]struct{}, c.size)
+	c.each(func(ext error) bool {
+		if isPlain(ext) {
+			c.index[ext] = struct{}{}
+		} else {
+			c.rest = append(c.rest, ext)
+		}
+		return false
+	})
+}
+
+// isPlain reports whether errors.Is(err, target) reduces to err == target.
+func isPlain(err error) bool {
+	if !reflect.TypeOf(err).Comparable() {
+		return false
+	}
+	switch err.(type) {
+	case interface{ Is(error) bool }, interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return false
+	}
+	return true
+}
+
+// each calls fn for every error in declaration order until fn returns true.
+func (c *chain) each(fn func(error) bool) bool {
+	if c == nil {
+		return false
+	}
+	if c.prev.each(fn) {
+		return true
+	}
+	for _, err := range c.errs {
+		if fn(err) {
+			return true
+		}
+	}
+	return false
 }
@@ -3,7 +3,11 @@ package knownerror
 import (
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -24,6 +28,178 @@ func TestNewf(t *testing.T) {
 	require.Equal(t, "error: some code 8234", err.Error())
 }
 
+func TestNewf__wrapped(t *testing.T) {
+	t.Parallel()
+
+	cause := New("some cause").Extends(ErrNotFound)
+	err := Newf("some context: %w", cause)
+
+	require.Equal(t, "some context: some cause", err.Error())
+	require.Same(t, cause, err.Cause())
+	require.True(t, errors.Is(err, cause))
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Equal(t, "some context: %w", err.template())
+	require.Nil(t, errors.Unwrap(errors.Unwrap(err)))
+}
+
+func TestNewf__wrapped_many(t *testing.T) {
+	t.Parallel()
+
+	cause1 := errors.New("some first cause")
+	cause2 := errors.New("some second cause")
+	err := Newf("some context: %w, %w", cause1, cause2)
+
+	require.Equal(t, "some context: some first cause, some second cause", err.Error())
+	require.Equal(t, []error{cause1, cause2}, err.Causes())
+	require.True(t, errors.Is(err, cause2))
+}
+
+func TestNewf__wrapped_cause_in_message(t *testing.T) {
+	IncludeCauseInMessage(true)
+	t.Cleanup(func() { IncludeCauseInMessage(false) })
+
+	err := Newf("some context: %w", errors.New("some cause"))
+
+	require.Equal(t, "some context: some cause", err.Error())
+}
+
+func TestNewf__message_without_cause(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	tests := []struct {
+		name string
+		got  struct {
+			err *Proxy
+		}
+		want struct {
+			message string
+		}
+	}{
+		{
+			name: "trailing",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("some context: %w", cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "some context",
+			},
+		},
+		{
+			name: "leading",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("%w: some context", cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "some context",
+			},
+		},
+		{
+			name: "middle",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("user %d: %w: some context", 42, cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "user 42: some context",
+			},
+		},
+		{
+			name: "many",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("some context %q: %w, %w", "some value", cause, cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: `some context "some value"`,
+			},
+		},
+		{
+			name: "indexed",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("some context: %[2]w (%[1]d%%)", 42, cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "some context (42%)",
+			},
+		},
+		{
+			name: "separator operand",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("some context %s: %w", ",", cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "some context ,",
+			},
+		},
+		{
+			name: "parenthesized",
+			got: struct {
+				err *Proxy
+			}{
+				err: Newf("some context (%w)", cause),
+			},
+			want: struct {
+				message string
+			}{
+				message: "some context",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want.message, tt.got.err.Message())
+		})
+	}
+}
+
+func TestNewf__wrapped_rewrapped(t *testing.T) {
+	t.Parallel()
+
+	err := Newf("some context: %w", errors.New("some cause")).WithCause(errors.New("some other cause"))
+
+	require.Equal(t, "some context", err.Error())
+	require.Equal(t, "some context: some other cause", err.WithCauseInMessage(true).Error())
+}
+
+func TestNewf__wrapped_rewrapped_is(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	otherCause := errors.New("some other cause")
+	err := Newf("some context: %w", cause)
+
+	require.False(t, errors.Is(err.WithCause(otherCause), otherCause))
+	require.False(t, errors.Is(err.WithCauses(otherCause, cause), otherCause))
+	require.False(t, errors.Is(err.Clone(CausedBy(otherCause)), otherCause))
+	require.True(t, errors.Is(err.WithCause(otherCause), cause))
+	require.True(t, errors.Is(err.WithCause(otherCause).WithTransparentCause(), otherCause))
+}
+
 func TestWrap(t *testing.T) {
 	t.Parallel()
 
@@ -69,6 +245,358 @@ func TestProxy_WithCause__preserves_identity(t *testing.T) {
 	require.True(t, errors.Is(result, outer))
 }
 
+func TestProxy_WithCauses(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	cause1 := errors.New("some first cause")
+	cause2 := New("some second cause").WithOp("some.Op")
+	result := sentinel.WithCauses(cause1, nil, cause2).WithTransparentCause()
+
+	require.Equal(t, []error{cause1, cause2}, result.Causes())
+	require.Equal(t, "some first cause; some second cause", result.Cause().Error())
+	require.Equal(t, "some error (cause: some first cause; some second cause)", fmt.Sprintf("%+v", result))
+	require.True(t, errors.Is(result, sentinel))
+	require.True(t, errors.Is(result, cause1))
+	require.True(t, errors.Is(result, cause2))
+	require.Equal(t, []string{"some.Op"}, Ops(result))
+}
+
+func TestProxy_WithCauses__single(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	cause := errors.New("some cause")
+
+	require.Same(t, sentinel, sentinel.WithCauses(nil))
+	require.Same(t, cause, sentinel.WithCauses(cause).Cause())
+	require.Equal(t, []error{cause}, sentinel.WithCause(cause).Causes())
+	require.Nil(t, sentinel.Causes())
+}
+
+func TestProxy_Clone(t *testing.T) {
+	t.Parallel()
+
+	errExternal := errors.New("some external")
+	sentinel := New("some error").Extends(ErrNotFound).WithHint("some hint")
+	result := sentinel.Clone(Message("some overridden error"), Categories(errExternal), Op("some.Op"))
+
+	require.NotSame(t, sentinel, result)
+	require.Equal(t, "some overridden error", result.Error())
+	require.Equal(t, "some hint", result.Hint())
+	require.Equal(t, "some.Op", result.Op())
+	require.True(t, errors.Is(result, sentinel))
+	require.True(t, errors.Is(result, ErrNotFound))
+	require.True(t, errors.Is(result, errExternal))
+	require.False(t, errors.Is(sentinel, errExternal))
+	require.Equal(t, "some error", sentinel.Error())
+	require.Empty(t, sentinel.Op())
+}
+
+func TestProxy_WithCauseInMessage(t *testing.T) {
+	t.Parallel()
+
+	cause := New("some cause").WithCause(errors.New("some root cause")).WithCauseInMessage(true)
+	err := New("some error").WithCause(cause).WithCauseInMessage(true)
+
+	require.Equal(t, "some error: some cause: some root cause", err.Error())
+	require.Equal(t, "some error", err.Message())
+	require.Equal(t, "some error: some cause: some root cause", fmt.Sprintf("%v", err))
+	require.Equal(t, "some error (cause: some cause: some root cause)", fmt.Sprintf("%+v", err))
+	require.Equal(t, "some error", New("some error").WithCauseInMessage(true).Error())
+}
+
+func TestIncludeCauseInMessage(t *testing.T) {
+	IncludeCauseInMessage(true)
+	t.Cleanup(func() { IncludeCauseInMessage(false) })
+
+	err := New("some error").WithCause(errors.New("some cause"))
+
+	require.Equal(t, "some error: some cause", err.Error())
+	require.Equal(t, "some error", err.WithCauseInMessage(false).Error())
+}
+
+func TestProxy_WithoutCategory(t *testing.T) {
+	t.Parallel()
+
+	errRetryable := errors.New("some retryable")
+	errUserFacing := errors.New("some user facing")
+	category := &customError{code: 503}
+	sentinel := New("some error").Extends(errRetryable, category, errUserFacing)
+	result := sentinel.WithCause(errors.New("some cause")).WithoutCategory(errRetryable, category, nil)
+
+	require.False(t, errors.Is(result, errRetryable))
+	require.False(t, errors.Is(fmt.Errorf("some context: %w", result.WithOp("some.Op")), errRetryable))
+	require.True(t, errors.Is(result, errUserFacing))
+	require.True(t, errors.Is(result, sentinel))
+	require.True(t, errors.Is(sentinel, errRetryable))
+	require.Equal(t, []error{errUserFacing}, ExtendsList(result))
+
+	var target *customError
+	require.False(t, errors.As(result, &target))
+	require.True(t, errors.As(sentinel, &target))
+
+	restored := result.Extends(errRetryable)
+	require.True(t, errors.Is(restored, errRetryable))
+	require.False(t, errors.Is(restored, category))
+
+	cloned := result.Clone(Categories(errRetryable))
+	require.True(t, errors.Is(cloned, errRetryable))
+	require.False(t, errors.Is(cloned, category))
+	require.False(t, errors.Is(result, errRetryable))
+}
+
+func TestProxy_WithoutCategory__transitive(t *testing.T) {
+	t.Parallel()
+
+	errUserNotFound := New("user not found").Extends(ErrNotFound)
+	result := New("some error").Extends(errUserNotFound).WithoutCategory(ErrNotFound)
+
+	require.True(t, errors.Is(result, errUserNotFound))
+	require.False(t, errors.Is(result, ErrNotFound))
+	require.Zero(t, StatusCode(result))
+}
+
+func TestProxy_WithoutCategory__wrapped_base(t *testing.T) {
+	t.Parallel()
+
+	errRetryable := errors.New("some retryable")
+	repoErr := New("some repo error").Extends(errRetryable)
+
+	require.True(t, errors.Is(Wrap(repoErr).WithoutCategory(errRetryable), errRetryable))
+	require.False(t, errors.Is(repoErr.WithoutCategory(errRetryable), errRetryable))
+}
+
+func TestProxy_WithOp(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithOp("some.Op")
+
+	require.Equal(t, "some.Op", result.Op())
+	require.Equal(t, "some error", result.Error())
+	require.True(t, errors.Is(result, sentinel))
+	require.Empty(t, sentinel.Op())
+}
+
+func TestProxy_WithOp__empty(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	require.Same(t, sentinel, sentinel.WithOp(""))
+}
+
+func TestProxy_WithTransparentCause(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	custom := &customError{code: 8234}
+	cause := fmt.Errorf("some cause: %w", custom)
+	hidden := sentinel.WithCause(cause)
+	result := hidden.WithTransparentCause()
+
+	require.False(t, errors.Is(hidden, cause))
+	require.True(t, errors.Is(result, cause))
+	require.True(t, errors.Is(result, sentinel))
+
+	var target *customError
+	require.False(t, errors.As(hidden, &target))
+	require.True(t, errors.As(result, &target))
+	require.Same(t, custom, target)
+}
+
+func TestProxy_WithTransparentCause__before_cause(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	result := New("some error").WithTransparentCause().WithCause(cause)
+
+	require.True(t, errors.Is(result, cause))
+	require.Same(t, result, result.WithTransparentCause())
+}
+
+func TestProxy_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithTimeout(true)
+
+	var netErr net.Error
+	require.True(t, errors.As(fmt.Errorf("some context: %w", result), &netErr))
+	require.True(t, netErr.Timeout())
+	require.True(t, IsTimeout(result))
+	require.True(t, errors.Is(result, sentinel))
+	require.True(t, errors.Is(netErr, sentinel))
+	require.False(t, IsTimeout(sentinel))
+}
+
+func TestProxy_WithTimeout__inherited(t *testing.T) {
+	t.Parallel()
+
+	var netErr net.Error
+	require.True(t, errors.As(Wrap(os.ErrDeadlineExceeded).WithTemporary(true), &netErr))
+	require.True(t, netErr.Timeout())
+	require.True(t, errors.As(New("some error").Extends(ErrTimeout).WithTemporary(true), &netErr))
+	require.True(t, netErr.Timeout())
+	require.True(t, IsTimeout(Wrap(fmt.Errorf("some context: %w", os.ErrDeadlineExceeded))))
+	require.False(t, IsTimeout(Wrap(os.ErrDeadlineExceeded).WithTimeout(false)))
+}
+
+func TestProxy_WithTemporary(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithTemporary(true)
+
+	require.True(t, isTemporary(result))
+	require.False(t, isTemporary(sentinel))
+	require.False(t, isTemporary(result.WithTemporary(false)))
+	require.True(t, isTemporary(Wrap(result)))
+	require.True(t, errors.Is(result, sentinel))
+}
+
+func TestProxy__not_net_error(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").Extends(ErrNotFound)
+
+	var netErr net.Error
+	require.False(t, errors.As(err, &netErr))
+	_, ok := AsPreferred[net.Error](err)
+	require.False(t, ok)
+	_, ok = any(err).(net.Error)
+	require.False(t, ok)
+
+	found, ok := AsPreferred[net.Error](err.WithTimeout(true))
+	require.True(t, ok)
+	require.True(t, errors.Is(found, err))
+	require.True(t, found.Timeout())
+}
+
+func isTemporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+func TestProxy_WithID(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithID("some-id")
+
+	require.Equal(t, "some-id", result.ID())
+	require.True(t, errors.Is(result, sentinel))
+	require.Empty(t, sentinel.ID())
+	require.Same(t, sentinel, sentinel.WithID(""))
+}
+
+func TestProxy_WithNewID(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	first := sentinel.WithNewID()
+	second := sentinel.WithNewID()
+
+	require.Len(t, first.ID(), 16)
+	require.NotEqual(t, first.ID(), second.ID())
+	require.True(t, errors.Is(first, sentinel))
+}
+
+func TestProxy_WithHelpURL(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithHelpURL("https://example.com/some-help")
+
+	require.Equal(t, "https://example.com/some-help", result.HelpURL())
+	require.True(t, errors.Is(result, sentinel))
+	require.Empty(t, sentinel.HelpURL())
+	require.Same(t, sentinel, sentinel.WithHelpURL(""))
+}
+
+func TestProxy_WithHint(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithHint("some hint")
+
+	require.Equal(t, "some hint", result.Hint())
+	require.True(t, errors.Is(result, sentinel))
+	require.Empty(t, sentinel.Hint())
+	require.Same(t, sentinel, sentinel.WithHint(""))
+}
+
+func TestProxy_WithPublicHint(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithHint("some internal hint")
+	result := sentinel.WithPublicHint("some hint")
+
+	require.Equal(t, "some hint", result.Hint())
+	require.Equal(t, "some hint", result.PublicHint())
+	require.Empty(t, sentinel.PublicHint())
+	require.Empty(t, result.WithHint("some other hint").PublicHint())
+	require.Same(t, sentinel, sentinel.WithPublicHint(""))
+}
+
+func TestProxy_WithTags(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").WithTags("some-tag")
+	result := sentinel.WithTags("some-other-tag", "", "some-tag")
+	other := sentinel.WithTags("some-third-tag")
+
+	require.Equal(t, []string{"some-tag"}, sentinel.Tags())
+	require.Equal(t, []string{"some-tag", "some-other-tag"}, result.Tags())
+	require.Equal(t, []string{"some-tag", "some-third-tag"}, other.Tags())
+	require.True(t, errors.Is(result, sentinel))
+}
+
+func TestProxy_WithDomain(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithDomain("some-domain")
+
+	require.Equal(t, "some-domain", result.Domain())
+	require.Empty(t, sentinel.Domain())
+	require.True(t, errors.Is(result, sentinel))
+}
+
+func TestProxy_WithRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	result := ErrTooManyRequests.WithRetryAfter(30 * time.Second)
+
+	require.Equal(t, 30*time.Second, result.RetryAfter())
+	require.Zero(t, ErrTooManyRequests.RetryAfter())
+	require.True(t, errors.Is(result, ErrTooManyRequests))
+}
+
+func TestProxy_WithExitCode(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	result := sentinel.WithExitCode(3)
+
+	require.Equal(t, 3, result.ExitCode())
+	require.True(t, errors.Is(result, sentinel))
+	require.Zero(t, sentinel.ExitCode())
+}
+
+func TestProxy_WithTimestamp(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error")
+	before := time.Now()
+	result := sentinel.WithTimestamp()
+
+	require.False(t, result.Timestamp().Before(before))
+	require.False(t, result.Timestamp().After(time.Now()))
+	require.True(t, errors.Is(result, sentinel))
+	require.True(t, sentinel.Timestamp().IsZero())
+}
+
 func TestProxy_Extends(t *testing.T) {
 	t.Parallel()
 
@@ -123,6 +651,19 @@ func TestProxy_Error__nil_base(t *testing.T) {
 	require.Empty(t, proxy.Error())
 }
 
+func TestProxy_Error__wrapped_proxy(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	for _, p := range []*Proxy{
+		Newf("some context: %w", cause),
+		New("some error").WithCause(cause).WithCauseInMessage(true),
+		New("some error").WithCause(cause),
+	} {
+		require.Equal(t, p.Error(), Wrap(p).Error())
+	}
+}
+
 func TestProxy_Unwrap(t *testing.T) {
 	t.Parallel()
 
@@ -183,6 +724,38 @@ func TestProxy_Is__nested_extends(t *testing.T) {
 	require.True(t, errors.Is(base, err2))
 }
 
+func TestProxy_Is__derived_chain(t *testing.T) {
+	t.Parallel()
+
+	ext1 := errors.New("some first extension")
+	ext2 := errors.New("some second extension")
+	sentinel := New("some error").Extends(ext1)
+	err := sentinel.WithCause(errors.New("some cause")).Extends(ext2).WithOp("some.Op")
+
+	require.True(t, errors.Is(err, sentinel))
+	require.True(t, errors.Is(err, ext1))
+	require.True(t, errors.Is(err, ext2))
+	require.False(t, errors.Is(sentinel, ext2))
+}
+
+func TestProxy_Is__many_extended(t *testing.T) {
+	t.Parallel()
+
+	categories := benchCategories(indexThreshold * 2)
+	plain := errors.New("some plain category")
+	nested := New("some nested category").Extends(plain)
+	uncomparable := uncomparableError{"some uncomparable category"}
+	err := New("some error").Extends(categories...).Extends(nested, uncomparable)
+
+	for _, category := range categories {
+		require.True(t, errors.Is(err, category))
+	}
+	require.True(t, errors.Is(err, nested))
+	require.True(t, errors.Is(err, plain))
+	require.False(t, errors.Is(err, errors.New("some other error")))
+	require.False(t, errors.Is(err, uncomparableError{"some other error"}))
+}
+
 func TestProxy_As(t *testing.T) {
 	t.Parallel()
 
@@ -194,6 +767,46 @@ func TestProxy_As(t *testing.T) {
 	require.Equal(t, 8234, target.code)
 }
 
+func TestProxy_As__declaration_order(t *testing.T) {
+	t.Parallel()
+
+	first := &customError{code: 1}
+	second := &customError{code: 2}
+	third := &customError{code: 3}
+	base := New("some base error").Extends(first, second).WithCause(errors.New("some cause")).Extends(third)
+
+	var target *customError
+	require.True(t, errors.As(base, &target))
+	require.Same(t, first, target)
+
+	base = New("some base error").Extends(third).Extends(second, first)
+	require.True(t, errors.As(base, &target))
+	require.Same(t, third, target)
+}
+
+func TestProxy_As__extract_proxy(t *testing.T) {
+	t.Parallel()
+
+	inner := New("some inner error")
+	err := fmt.Errorf("some context: %w", fmt.Errorf("some more context: %w", inner))
+
+	var p *Proxy
+	require.True(t, errors.As(err, &p))
+	require.Same(t, inner, p)
+}
+
+func TestProxy_As__extract_outermost_proxy(t *testing.T) {
+	t.Parallel()
+
+	inner := New("some inner error").Extends(New("some category"))
+	outer := Wrap(fmt.Errorf("some context: %w", inner)).WithCause(New("some cause"))
+	err := errors.Join(errors.New("some other error"), outer)
+
+	var p *Proxy
+	require.True(t, errors.As(err, &p))
+	require.Same(t, outer, p)
+}
+
 func TestProxy_As__non_matching_type(t *testing.T) {
 	t.Parallel()
 
@@ -306,6 +919,56 @@ func TestProxy_Format__plus_v_nested_cause(t *testing.T) {
 	require.Equal(t, "some main error (cause: some outer cause)", result)
 }
 
+func TestProxy_Format__plus_v_wrapped_by_newf(t *testing.T) {
+	t.Parallel()
+
+	err := Newf("some context: %w", errors.New("some cause")).WithID("some-id")
+	require.Equal(t, "some context: some cause (id: some-id)", fmt.Sprintf("%+v", err))
+}
+
+func TestProxy_Format__plus_v_wrapped_proxy(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("some cause")
+	require.Equal(t, "some context: some cause (id: some-id)",
+		fmt.Sprintf("%+v", Wrap(Newf("some context: %w", cause)).WithID("some-id")))
+	require.Equal(t, "some error (cause: some cause)",
+		fmt.Sprintf("%+v", Wrap(New("some error").WithCause(cause))))
+}
+
+func TestProxy_Format__plus_v_with_id(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").WithID("some-id")
+	require.Equal(t, "some error (id: some-id)", fmt.Sprintf("%+v", err))
+
+	err = err.WithCause(errors.New("some root cause"))
+	require.Equal(t, "some error (id: some-id, cause: some root cause)", fmt.Sprintf("%+v", err))
+}
+
+func TestProxy_Format__no_allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+
+	ext := errors.New("some extension")
+	err := New("some error").Extends(ext).WithCause(errors.New("some cause")).WithID("some-id")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = err.Error()
+		_ = errors.Is(err, ext)
+		_, _ = fmt.Fprintf(io.Discard, "%s %v %+v", err, err, err)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestProxy_Format__plus_v_with_hint(t *testing.T) {
+	t.Parallel()
+
+	err := New("some error").WithHint("some hint").WithCause(errors.New("some root cause")).WithID("some-id")
+	require.Equal(t, "some error (id: some-id, hint: some hint, cause: some root cause)", fmt.Sprintf("%+v", err))
+}
+
 type customError struct {
 	code int
 }
@@ -313,3 +976,76 @@ type customError struct {
 func (e *customError) Error() string {
 	return "custom error"
 }
+
+func BenchmarkProxy_WithCause(b *testing.B) {
+	sentinel := New("some error").Extends(benchCategories(16)...)
+	cause := errors.New("some cause")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchSink = sentinel.WithCause(cause)
+	}
+}
+
+func BenchmarkProxy_Extends(b *testing.B) {
+	sentinel := New("some error").Extends(benchCategories(16)...)
+	ext := errors.New("some extension")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchSink = sentinel.Extends(ext)
+	}
+}
+
+func BenchmarkProxy_Is__miss(b *testing.B) {
+	err := New("some error").Extends(benchCategories(32)...).WithCause(errors.New("some cause"))
+	target := errors.New("some other error")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Is(err, target)
+	}
+}
+
+func BenchmarkProxy_Is(b *testing.B) {
+	categories := benchCategories(16)
+	err := New("some error").Extends(categories...).WithCause(errors.New("some cause")).WithOp("some.Op")
+	target := categories[len(categories)-1]
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Is(err, target)
+	}
+}
+
+func BenchmarkProxy_Error(b *testing.B) {
+	err := New("some error").WithCause(errors.New("some cause"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func BenchmarkProxy_Format(b *testing.B) {
+	err := New("some error").WithCause(errors.New("some cause")).WithID("some-id")
+	for _, format := range []string{"%s", "%v", "%+v", "%q"} {
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = fmt.Fprintf(io.Discard, format, err)
+			}
+		})
+	}
+}
+
+var benchSink *Proxy
+
+func benchCategories(n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = fmt.Errorf("some category %d", i)
+	}
+	return errs
+}
+
+type uncomparableError []string
+
+func (e uncomparableError) Error() string {
+	return e[0]
+}
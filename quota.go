@@ -0,0 +1,55 @@
+package knownerror
+
+import (
+	"strconv"
+	"time"
+)
+
+// ErrResourceExhausted is the known category for exceeded rate limits and
+// quotas. It extends ErrTooManyRequests.
+var ErrResourceExhausted = New("resource exhausted").Extends(ErrTooManyRequests)
+
+// QuotaDetail describes an exhausted quota, attached by QuotaExceeded and
+// extracted with QuotaOf. It is not a category: it does not show up in
+// ExtendsList, KeyValues or Fingerprint.
+type QuotaDetail struct {
+	// Limit is the quota size.
+	Limit int
+	// Remaining is what is left of the quota, usually 0.
+	Remaining int
+	// Reset is when the quota is replenished. Zero if unknown.
+	Reset time.Time
+}
+
+// String describes the quota, e.g. "quota of 100 exceeded, 0 remaining".
+func (d QuotaDetail) String() string {
+	return "quota of " + strconv.Itoa(d.Limit) + " exceeded, " + strconv.Itoa(d.Remaining) + " remaining"
+}
+
+// QuotaExceeded returns an ErrResourceExhausted carrying d. If d.Reset is in
+// the future, the error also reports the time until the reset via RetryAfter:
+//
+//	return knownerror.QuotaExceeded(knownerror.QuotaDetail{Limit: 100, Reset: window.End})
+func QuotaExceeded(d QuotaDetail) *Proxy {
+	err := ErrResourceExhausted.derive()
+	err.setMeta().quota = &d
+	if wait := time.Until(d.Reset); !d.Reset.IsZero() && wait > 0 {
+		err = err.WithRetryAfter(wait)
+	}
+	return err
+}
+
+// QuotaOf extracts the QuotaDetail of the outermost Proxy in err's chain that
+// has one.
+func QuotaOf(err error) (QuotaDetail, bool) {
+	var d *QuotaDetail
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && d == nil {
+			d = p.md().quota
+		}
+	})
+	if d == nil {
+		return QuotaDetail{}, false
+	}
+	return *d, true
+}
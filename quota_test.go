@@ -0,0 +1,57 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	reset := time.Now().Add(time.Hour)
+	err := fmt.Errorf("some context: %w", QuotaExceeded(QuotaDetail{Limit: 100, Reset: reset}).WithOp("some.Op"))
+
+	d, ok := QuotaOf(err)
+	require.True(t, ok)
+	require.Equal(t, QuotaDetail{Limit: 100, Reset: reset}, d)
+	require.Equal(t, "quota of 100 exceeded, 0 remaining", d.String())
+	require.True(t, errors.Is(err, ErrResourceExhausted))
+	require.True(t, errors.Is(err, ErrTooManyRequests))
+	require.Equal(t, 429, StatusCode(err))
+	require.InDelta(t, time.Hour, RetryAfterOf(err), float64(time.Minute))
+}
+
+func TestQuotaExceeded__not_a_category(t *testing.T) {
+	t.Parallel()
+
+	a := QuotaExceeded(QuotaDetail{Limit: 100, Remaining: 1})
+	b := QuotaExceeded(QuotaDetail{Limit: 200})
+
+	require.Equal(t, ExtendsList(ErrResourceExhausted), ExtendsList(a))
+	require.Equal(t, KeyValues(ErrResourceExhausted), KeyValues(a))
+	require.Equal(t, Fingerprint(ErrResourceExhausted), Fingerprint(a))
+	require.Equal(t, Fingerprint(a), Fingerprint(b))
+}
+
+func TestQuotaExceeded__no_reset(t *testing.T) {
+	t.Parallel()
+
+	err := QuotaExceeded(QuotaDetail{Limit: 100, Remaining: 1})
+
+	require.Zero(t, err.RetryAfter())
+	require.Zero(t, QuotaExceeded(QuotaDetail{Reset: time.Now().Add(-time.Hour)}).RetryAfter())
+}
+
+func TestQuotaOf__missing(t *testing.T) {
+	t.Parallel()
+
+	_, ok := QuotaOf(ErrResourceExhausted)
+	require.False(t, ok)
+
+	_, ok = QuotaOf(nil)
+	require.False(t, ok)
+}
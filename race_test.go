@@ -0,0 +1,5 @@
+//go:build race
+
+package knownerror
+
+const raceEnabled = true
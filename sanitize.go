@@ -0,0 +1,92 @@
+package knownerror
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sanitizer cleans up messages that may come from third-party errors or user
+// input before they are rendered, e.g. to prevent log injection.
+type Sanitizer struct {
+	// MaxLen caps messages at MaxLen bytes, cutting on a rune boundary and
+	// appending "...". Zero means no limit.
+	MaxLen int
+	// Escape writes control characters as Go escapes (\n, \x1b, ...) instead
+	// of replacing line breaks and tabs with spaces and dropping the rest.
+	Escape bool
+}
+
+var sanitizer atomic.Pointer[Sanitizer]
+
+// SetSanitizer makes Proxies sanitize the messages they render with s: their
+// own message and their causes', in Error, Format, the encodings and the
+// logging and HTTP integrations. A nil s disables sanitization, the default:
+//
+//	knownerror.SetSanitizer(&knownerror.Sanitizer{MaxLen: 1024})
+//	knownerror.Wrap(errors.New("bad\nline")).Error() // "bad line"
+//
+// Use Sanitize for other messages rendered alongside known errors.
+func SetSanitizer(s *Sanitizer) {
+	if s != nil {
+		cpy := *s
+		s = &cpy
+	}
+	sanitizer.Store(s)
+}
+
+// Sanitize applies the Sanitizer set via SetSanitizer to msg, if any.
+func Sanitize(msg string) string {
+	if s := sanitizer.Load(); s != nil {
+		return s.Sanitize(msg)
+	}
+	return msg
+}
+
+// Sanitize replaces invalid UTF-8, removes or escapes control characters and
+// caps msg at MaxLen bytes.
+func (s Sanitizer) Sanitize(msg string) string {
+	if s.clean(msg) {
+		return msg
+	}
+	var b strings.Builder
+	for _, r := range strings.ToValidUTF8(msg, string(utf8.RuneError)) {
+		switch {
+		case !isControl(r):
+			b.WriteRune(r)
+		case s.Escape:
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		case r == '\n' || r == '\r' || r == '\t' || r == '\u2028' || r == '\u2029':
+			b.WriteByte(' ')
+		}
+	}
+	msg = b.String()
+	if s.MaxLen > 0 && len(msg) > s.MaxLen {
+		cut := s.MaxLen
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		msg = msg[:cut] + "..."
+	}
+	return msg
+}
+
+// clean reports whether msg needs no changes.
+func (s Sanitizer) clean(msg string) bool {
+	if s.MaxLen > 0 && len(msg) > s.MaxLen || !utf8.ValidString(msg) {
+		return false
+	}
+	for _, r := range msg {
+		if isControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isControl(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
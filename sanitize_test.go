@@ -0,0 +1,112 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizer_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		got  struct {
+			sanitizer Sanitizer
+			msg       string
+		}
+		want struct {
+			msg string
+		}
+	}{
+		{
+			name: "clean",
+			got: struct {
+				sanitizer Sanitizer
+				msg       string
+			}{
+				sanitizer: Sanitizer{MaxLen: 10},
+				msg:       "some error",
+			},
+			want: struct {
+				msg string
+			}{
+				msg: "some error",
+			},
+		},
+		{
+			name: "strip",
+			got: struct {
+				sanitizer Sanitizer
+				msg       string
+			}{
+				sanitizer: Sanitizer{},
+				msg:       "some\nerror\x1b[31m\u2028\xff",
+			},
+			want: struct {
+				msg string
+			}{
+				msg: "some error[31m \ufffd",
+			},
+		},
+		{
+			name: "escape",
+			got: struct {
+				sanitizer Sanitizer
+				msg       string
+			}{
+				sanitizer: Sanitizer{Escape: true},
+				msg:       "some\nerror\x1b",
+			},
+			want: struct {
+				msg string
+			}{
+				msg: `some\nerror\x1b`,
+			},
+		},
+		{
+			name: "cap",
+			got: struct {
+				sanitizer Sanitizer
+				msg       string
+			}{
+				sanitizer: Sanitizer{MaxLen: 6},
+				msg:       "some ошибка",
+			},
+			want: struct {
+				msg string
+			}{
+				msg: "some ...",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want.msg, tt.got.sanitizer.Sanitize(tt.got.msg))
+		})
+	}
+}
+
+func TestSetSanitizer(t *testing.T) {
+	SetSanitizer(&Sanitizer{MaxLen: 20})
+	t.Cleanup(func() { SetSanitizer(nil) })
+
+	cause := errors.New("some\ncause")
+	err := Wrap(errors.New("some\r\nuntrusted error message")).WithCause(cause).WithCauseInMessage(true)
+
+	require.Equal(t, "some  untrusted erro...: some cause", err.Error())
+	require.Equal(t, "some  untrusted erro... (cause: some cause)", fmt.Sprintf("%+v", err))
+	require.Equal(t, []any{"message", "some  untrusted erro...", "cause", "some cause"}, KeyValues(err))
+	require.Equal(t, "some cause", Sanitize(cause.Error()))
+}
+
+func TestSanitize__disabled(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "some\nerror", Sanitize("some\nerror"))
+}
@@ -0,0 +1,87 @@
+package knownerror
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttler limits how often identical errors are reported, keyed by
+// Fingerprint. Within each window the first limit occurrences of an error are
+// allowed; the rest are suppressed and counted. It is safe for concurrent use:
+//
+//	t := knownerror.NewThrottler(10, time.Minute)
+//	if ok, suppressed := t.Allow(err); ok {
+//		logger.Error("request failed", "err", err, "suppressed", suppressed)
+//	}
+type Throttler struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*throttleEntry
+	swept   time.Time
+}
+
+type throttleEntry struct {
+	start      time.Time
+	seen       int
+	suppressed int
+}
+
+// NewThrottler creates a Throttler that allows limit occurrences of each error
+// per window. A limit below 1 is treated as 1.
+func NewThrottler(limit int, window time.Duration) *Throttler {
+	return &Throttler{
+		limit:   max(limit, 1),
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*throttleEntry),
+	}
+}
+
+// Allow reports whether err should be reported in full. When it returns true,
+// suppressed is the number of occurrences suppressed since the error was last
+// allowed. A nil error is never allowed.
+func (t *Throttler) Allow(err error) (ok bool, suppressed int) {
+	if err == nil {
+		return false, 0
+	}
+	key := Fingerprint(err)
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sweep(now)
+
+	e := t.entries[key]
+	if e == nil {
+		e = &throttleEntry{start: now}
+		t.entries[key] = e
+	} else if now.Sub(e.start) >= t.window {
+		e.start, e.seen = now, 0
+	}
+	if e.seen >= t.limit {
+		e.suppressed++
+		return false, 0
+	}
+	e.seen++
+	suppressed, e.suppressed = e.suppressed, 0
+	return true, suppressed
+}
+
+// sweep forgets errors that have not been seen for a while, at most once per
+// window. Suppressed counts are kept for one extra window so that they can be
+// reported when the error recurs.
+func (t *Throttler) sweep(now time.Time) {
+	if now.Sub(t.swept) < t.window {
+		return
+	}
+	t.swept = now
+	for key, e := range t.entries {
+		age := now.Sub(e.start)
+		if age >= 2*t.window || (age >= t.window && e.suppressed == 0) {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package knownerror
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottler_Allow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	th := NewThrottler(2, time.Minute)
+	th.now = func() time.Time { return now }
+
+	err := Newf("user %d not found", 1)
+	other := New("some other error")
+
+	ok, suppressed := th.Allow(err)
+	require.True(t, ok)
+	require.Zero(t, suppressed)
+
+	ok, _ = th.Allow(Newf("user %d not found", 2))
+	require.True(t, ok)
+
+	for range 3 {
+		ok, _ = th.Allow(err)
+		require.False(t, ok)
+	}
+
+	ok, _ = th.Allow(other)
+	require.True(t, ok)
+
+	now = now.Add(time.Minute)
+	ok, suppressed = th.Allow(err)
+	require.True(t, ok)
+	require.Equal(t, 3, suppressed)
+}
+
+func TestThrottler_Allow__sweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	th := NewThrottler(1, time.Minute)
+	th.now = func() time.Time { return now }
+
+	th.Allow(New("some error"))
+	th.Allow(New("some error"))
+	th.Allow(New("some other error"))
+	require.Len(t, th.entries, 2)
+
+	now = now.Add(time.Minute)
+	th.Allow(New("some third error"))
+	require.Len(t, th.entries, 2)
+
+	now = now.Add(time.Minute)
+	th.Allow(New("some third error"))
+	require.Len(t, th.entries, 1)
+}
+
+func TestThrottler_Allow__nil(t *testing.T) {
+	t.Parallel()
+
+	th := NewThrottler(0, time.Minute)
+
+	ok, _ := th.Allow(nil)
+	require.False(t, ok)
+
+	ok, _ = th.Allow(errors.New("some error"))
+	require.True(t, ok)
+	ok, _ = th.Allow(errors.New("some error"))
+	require.False(t, ok)
+}
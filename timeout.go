@@ -0,0 +1,54 @@
+package knownerror
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrTimeout is the known category for operations that ran out of time.
+	// It extends context.DeadlineExceeded and ErrGatewayTimeout.
+	ErrTimeout = New("timeout").Extends(context.DeadlineExceeded, ErrGatewayTimeout)
+
+	// ErrCanceled is the known category for canceled operations.
+	// It extends context.Canceled.
+	ErrCanceled = New("canceled").Extends(context.Canceled)
+)
+
+// IsTimeout reports whether err is a timeout: context.DeadlineExceeded,
+// ErrTimeout, or any error in the chain with a Timeout() method returning true
+// (net.Error, os.ErrDeadlineExceeded, ...).
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrTimeout) {
+		return true
+	}
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// IsCanceled reports whether err is context.Canceled or ErrCanceled.
+func IsCanceled(err error) bool {
+	return err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, ErrCanceled))
+}
+
+// ClassifyContext promotes timeouts and cancellations into ErrTimeout and
+// ErrCanceled with err attached as the cause. Other errors, and errors already
+// classified, are returned as is:
+//
+//	if err := client.Do(ctx, req); err != nil {
+//		return knownerror.ClassifyContext(err)
+//	}
+func ClassifyContext(err error) error {
+	switch {
+	case err == nil, errors.Is(err, ErrTimeout), errors.Is(err, ErrCanceled):
+		return err
+	case IsTimeout(err):
+		return ErrTimeout.WithCause(err)
+	case IsCanceled(err):
+		return ErrCanceled.WithCause(err)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package knownerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTimeout(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsTimeout(context.DeadlineExceeded))
+	require.True(t, IsTimeout(fmt.Errorf("some context: %w", os.ErrDeadlineExceeded)))
+	require.True(t, IsTimeout(&net.DNSError{Err: "some dns error", IsTimeout: true}))
+	require.True(t, IsTimeout(New("some error").Extends(ErrTimeout)))
+	require.False(t, IsTimeout(&net.DNSError{Err: "some dns error"}))
+	require.False(t, IsTimeout(context.Canceled))
+	require.False(t, IsTimeout(nil))
+}
+
+func TestIsCanceled(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsCanceled(fmt.Errorf("some context: %w", context.Canceled)))
+	require.True(t, IsCanceled(New("some error").Extends(ErrCanceled)))
+	require.False(t, IsCanceled(context.DeadlineExceeded))
+	require.False(t, IsCanceled(nil))
+}
+
+func TestClassifyContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ClassifyContext(ctx.Err())
+	require.True(t, errors.Is(err, ErrCanceled))
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Same(t, context.Canceled, err.(*Proxy).Cause())
+
+	err = ClassifyContext(os.ErrDeadlineExceeded)
+	require.True(t, errors.Is(err, ErrTimeout))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	known := ErrTimeout.WithCause(errors.New("some cause"))
+	require.Same(t, known, ClassifyContext(known))
+
+	other := errors.New("some error")
+	require.Same(t, other, ClassifyContext(other))
+	require.NoError(t, ClassifyContext(nil))
+}
@@ -0,0 +1,80 @@
+package knownerror
+
+import (
+	"errors"
+	"sync"
+)
+
+var transients struct {
+	mu  sync.RWMutex
+	fns []func(error) bool
+}
+
+// RegisterTransient adds a predicate consulted by IsTransient, for errors of
+// third-party libraries that IsTransient does not know about:
+//
+//	knownerror.RegisterTransient(func(err error) bool {
+//		var pgErr *pgconn.PgError
+//		return errors.As(err, &pgErr) && pgErr.Code == "40001" // serialization failure
+//	})
+//
+// Register predicates during program initialization.
+func RegisterTransient(fn func(error) bool) {
+	if fn == nil {
+		return
+	}
+	transients.mu.Lock()
+	defer transients.mu.Unlock()
+	transients.fns = append(transients.fns, fn)
+}
+
+// IsTransient reports whether err is worth retrying. In order:
+//
+//   - cancellations are never transient;
+//   - the value set via WithTemporary on the outermost Proxy that has one wins;
+//   - timeouts, errors whose Temporary method reports true (temporary DNS
+//     failures, ...) and connection resets, aborts and refusals are transient;
+//   - so are errors with status 408, 425, 429, 500, 502, 503 or 504;
+//   - finally, predicates registered via RegisterTransient are consulted.
+func IsTransient(err error) bool {
+	if err == nil || IsCanceled(err) {
+		return false
+	}
+	if temporary := explicitTemporary(err); temporary != flagUnset {
+		return temporary == flagTrue
+	}
+	if IsTimeout(err) {
+		return true
+	}
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) && t.Temporary() {
+		return true
+	}
+	if isConnError(err) {
+		return true
+	}
+	switch StatusCode(err) {
+	case 408, 425, 429, 500, 502, 503, 504:
+		return true
+	}
+	transients.mu.RLock()
+	defer transients.mu.RUnlock()
+	for _, fn := range transients.fns {
+		if fn(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// explicitTemporary returns the flag set via WithTemporary on the outermost
+// Proxy in err's chain that has one.
+func explicitTemporary(err error) flag {
+	temporary := flagUnset
+	walk(err, func(err error) {
+		if p, ok := err.(*Proxy); ok && temporary == flagUnset {
+			temporary = p.temporary
+		}
+	})
+	return temporary
+}
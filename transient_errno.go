@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package knownerror
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isConnError reports whether err is a connection reset, abort or refusal.
+func isConnError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,22 @@
+//go:build !plan9
+
+package knownerror
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient__connection_errors(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsTransient(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}))
+	require.True(t, IsTransient(&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.ECONNABORTED)}))
+	require.True(t, IsTransient(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}))
+	require.False(t, IsTransient(&net.OpError{Op: "dial", Err: errors.New("some error")}))
+}
@@ -0,0 +1,7 @@
+package knownerror
+
+// isConnError reports false: Plan 9 has no errno values for connection
+// failures, they are reported as plain error strings.
+func isConnError(error) bool {
+	return false
+}
@@ -0,0 +1,165 @@
+package knownerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		got  struct {
+			err error
+		}
+		want struct {
+			transient bool
+		}
+	}{
+		{
+			name: "nil",
+			got: struct {
+				err error
+			}{
+				err: nil,
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: false,
+			},
+		},
+		{
+			name: "canceled",
+			got: struct {
+				err error
+			}{
+				err: fmt.Errorf("some context: %w", context.Canceled),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: false,
+			},
+		},
+		{
+			name: "timeout",
+			got: struct {
+				err error
+			}{
+				err: fmt.Errorf("some context: %w", os.ErrDeadlineExceeded),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: true,
+			},
+		},
+		{
+			name: "temporary_dns",
+			got: struct {
+				err error
+			}{
+				err: &net.DNSError{Err: "some dns error", IsTemporary: true},
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: true,
+			},
+		},
+		{
+			name: "status_category",
+			got: struct {
+				err error
+			}{
+				err: New("some error").Extends(ErrServiceUnavailable),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: true,
+			},
+		},
+		{
+			name: "permanent_status_category",
+			got: struct {
+				err error
+			}{
+				err: New("some error").Extends(ErrNotImplemented),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: false,
+			},
+		},
+		{
+			name: "explicit_temporary",
+			got: struct {
+				err error
+			}{
+				err: New("some error").WithTemporary(true),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: true,
+			},
+		},
+		{
+			name: "explicit_not_temporary",
+			got: struct {
+				err error
+			}{
+				err: fmt.Errorf("some context: %w", ErrServiceUnavailable.WithTemporary(false)),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: false,
+			},
+		},
+		{
+			name: "unknown",
+			got: struct {
+				err error
+			}{
+				err: errors.New("some error"),
+			},
+			want: struct {
+				transient bool
+			}{
+				transient: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want.transient, IsTransient(tt.got.err))
+		})
+	}
+}
+
+func TestRegisterTransient(t *testing.T) {
+	err := &customError{code: 40001}
+	require.False(t, IsTransient(err))
+
+	RegisterTransient(func(err error) bool {
+		var c *customError
+		return errors.As(err, &c) && c.code == 40001
+	})
+
+	require.True(t, IsTransient(fmt.Errorf("some context: %w", err)))
+	require.False(t, IsTransient(&customError{code: 40002}))
+}
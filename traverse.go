@@ -0,0 +1,83 @@
+package knownerror
+
+import "reflect"
+
+// maxDepth bounds how many errors is and as follow before giving up. Steps
+// from a derived Proxy to the one it was derived from are not counted.
+const maxDepth = 100
+
+// is mirrors errors.Is, but expands Proxies in place instead of calling their
+// Is hook and stops after maxDepth steps.
+func is(err, target error, depth int) bool {
+	isComparable := reflect.TypeOf(target).Comparable()
+	for ; err != nil && depth <= maxDepth; depth++ {
+		if isComparable && err == target {
+			return true
+		}
+		switch x := err.(type) {
+		case *Proxy:
+			if x.extendsIs(target, depth) {
+				return true
+			}
+			err = x.base
+			continue
+		case interface{ Is(error) bool }:
+			if x.Is(target) {
+				return true
+			}
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				if is(err, target, depth+1) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// as mirrors errors.As, but expands Proxies in place instead of calling their
+// As hook and stops after maxDepth steps. target is validated by errors.As.
+func as(err error, target any, depth int) bool {
+	val := reflect.ValueOf(target)
+	targetType := val.Type().Elem()
+	for ; err != nil && depth <= maxDepth; depth++ {
+		if reflect.TypeOf(err).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+		switch x := err.(type) {
+		case *Proxy:
+			if x.extendsAs(target, depth) {
+				return true
+			}
+			err = x.base
+			continue
+		case interface{ As(any) bool }:
+			if x.As(target) {
+				return true
+			}
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				if as(err, target, depth+1) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
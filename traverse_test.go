@@ -0,0 +1,87 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIs__cycle(t *testing.T) {
+	t.Parallel()
+
+	loop := &mutableError{}
+	err := New("some error").Extends(loop)
+	loop.inner = err
+
+	require.False(t, errors.Is(err, errors.New("some other error")))
+	require.True(t, errors.Is(err, loop))
+}
+
+func TestAs__cycle(t *testing.T) {
+	t.Parallel()
+
+	loop := &mutableError{}
+	err := New("some error").Extends(loop)
+	loop.inner = err
+
+	var target *customError
+	require.False(t, errors.As(err, &target))
+}
+
+func TestWalk__cycle(t *testing.T) {
+	t.Parallel()
+
+	loop := &mutableError{}
+	err := Wrap(loop).WithOp("some.Op")
+	loop.inner = err
+
+	require.Len(t, Ops(err), maxDepth/2+1)
+}
+
+func TestIs__long_derivation(t *testing.T) {
+	t.Parallel()
+
+	category := errors.New("some category")
+	original := New("some error").Extends(category)
+	err := original
+	for i := range 120 {
+		err = err.WithOp(fmt.Sprintf("some.Op%d", i))
+	}
+
+	require.True(t, errors.Is(err, original))
+	require.True(t, errors.Is(err, category))
+
+	var target *Proxy
+	require.True(t, errors.As(fmt.Errorf("some context: %w", err), &target))
+	require.True(t, errors.Is(target, original))
+}
+
+func TestIs__through_wrappers(t *testing.T) {
+	t.Parallel()
+
+	category := errors.New("some category")
+	nested := New("some nested error").Extends(category)
+	custom := &customError{code: 8234}
+	err := New("some error").Extends(fmt.Errorf("some context: %w", nested), errors.Join(nil, custom))
+
+	require.True(t, errors.Is(err, category))
+	require.True(t, errors.Is(err, nested))
+
+	var target *customError
+	require.True(t, errors.As(err, &target))
+	require.Same(t, custom, target)
+}
+
+type mutableError struct {
+	inner error
+}
+
+func (e *mutableError) Error() string {
+	return "some mutable error"
+}
+
+func (e *mutableError) Unwrap() error {
+	return e.inner
+}
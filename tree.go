@@ -0,0 +1,106 @@
+package knownerror
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tree renders err's structure as an indented tree: the extended errors,
+// wrapped base errors and causes of each Proxy, and what other errors wrap:
+//
+//	fmt.Println(knownerror.Tree(err))
+//	// user not found
+//	//   extends: not found
+//	//   cause: query failed (*fmt.wrapError)
+//	//     wraps: sql: no rows in result set (*errors.errorString)
+//
+// Errors other than Proxies are annotated with their type. Returns "" for a
+// nil error.
+func Tree(err error) string {
+	var b strings.Builder
+	for _, n := range treeNodes(err) {
+		b.WriteString(strings.Repeat("  ", n.depth))
+		if n.edge != "" {
+			b.WriteString(n.edge)
+			b.WriteString(": ")
+		}
+		b.WriteString(n.label)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// DOT renders err's structure, as described for Tree, in the Graphviz DOT
+// language:
+//
+//	os.WriteFile("err.dot", []byte(knownerror.DOT(err)), 0o644)
+//	// dot -Tsvg err.dot > err.svg
+func DOT(err error) string {
+	var b strings.Builder
+	b.WriteString("digraph error {\n")
+	for _, n := range treeNodes(err) {
+		fmt.Fprintf(&b, "  n%d [label=%s];\n", n.id, strconv.Quote(n.label))
+		if n.parent >= 0 {
+			fmt.Fprintf(&b, "  n%d -> n%d [label=%s];\n", n.parent, n.id, strconv.Quote(n.edge))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type treeNode struct {
+	id, parent int
+	depth      int
+	edge       string
+	label      string
+}
+
+// treeNodes flattens err's structure in depth-first order, up to maxDepth
+// levels deep.
+func treeNodes(err error) []treeNode {
+	var nodes []treeNode
+	var add func(err error, edge string, parent, depth int)
+	add = func(err error, edge string, parent, depth int) {
+		if err == nil || depth > maxDepth {
+			return
+		}
+		id := len(nodes)
+		label := messageOf(err)
+		if _, ok := err.(*Proxy); !ok {
+			label += fmt.Sprintf(" (%T)", err)
+		}
+		nodes = append(nodes, treeNode{id: id, parent: parent, depth: depth, edge: edge, label: label})
+
+		switch x := err.(type) {
+		case *Proxy:
+			for _, ext := range x.extendsList() {
+				add(ext, "extends", id, depth+1)
+			}
+			if isWrapper(x.base) {
+				add(x.base, "base", id, depth+1)
+			}
+			for _, cause := range x.Causes() {
+				add(cause, "cause", id, depth+1)
+			}
+		case interface{ Unwrap() error }:
+			add(x.Unwrap(), "wraps", id, depth+1)
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				add(err, "wraps", id, depth+1)
+			}
+		}
+	}
+	add(err, "", -1, 0)
+	return nodes
+}
+
+// isWrapper reports whether err is worth showing as a Proxy's base: another
+// Proxy or an error wrapping others, rather than a plain message.
+func isWrapper(err error) bool {
+	switch err.(type) {
+	case *Proxy, interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package knownerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("some error").Extends(New("some category").Extends(errors.New("some parent category")))
+	cause := fmt.Errorf("some context: %w", errors.New("some cause"))
+	err := Wrap(sentinel.WithCauses(cause, errors.New("some other cause")))
+
+	want := "some error\n" +
+		"  base: some error\n" +
+		"    extends: some category\n" +
+		"      extends: some parent category (*errors.errorString)\n" +
+		"    cause: some context: some cause (*fmt.wrapError)\n" +
+		"      wraps: some cause (*errors.errorString)\n" +
+		"    cause: some other cause (*errors.errorString)\n"
+	require.Equal(t, want, Tree(err))
+}
+
+func TestTree__cause_in_message(t *testing.T) {
+	t.Parallel()
+
+	err := Wrap(New("some error").WithCause(errors.New("some cause")).WithCauseInMessage(true))
+
+	want := "some error\n" +
+		"  base: some error\n" +
+		"    cause: some cause (*errors.errorString)\n"
+	require.Equal(t, want, Tree(err))
+}
+
+func TestTree__nil(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Tree(nil))
+}
+
+func TestDOT(t *testing.T) {
+	t.Parallel()
+
+	err := New("some \"quoted\" error").WithCause(errors.New("some cause"))
+
+	want := "digraph error {\n" +
+		"  n0 [label=\"some \\\"quoted\\\" error\"];\n" +
+		"  n1 [label=\"some cause (*errors.errorString)\"];\n" +
+		"  n0 -> n1 [label=\"cause\"];\n" +
+		"}\n"
+	require.Equal(t, want, DOT(err))
+}
@@ -0,0 +1,100 @@
+package knownerror
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is the category matched by every ValidationError. It extends ErrBadRequest.
+var ErrInvalid = New("invalid input").Extends(ErrBadRequest)
+
+// FieldError is a known error reported for a single input field.
+type FieldError struct {
+	Path string
+	Err  *Proxy
+}
+
+// Error returns "path: message".
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap is a hook for errors.Unwrap. Returns the field's known error.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError collects per-field known errors. It matches ErrInvalid and
+// every field error via errors.Is:
+//
+//	var v knownerror.ValidationError
+//	if req.Name == "" {
+//		v.Add("name", ErrRequired)
+//	}
+//	if len(req.Password) < 8 {
+//		v.Add("password", ErrTooShort)
+//	}
+//	return v.Err()
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add records err for the field at path. Nil errors are ignored.
+func (e *ValidationError) Add(path string, err *Proxy) {
+	if err == nil {
+		return
+	}
+	e.Fields = append(e.Fields, FieldError{Path: path, Err: err})
+}
+
+// Err returns e if any field error was added, otherwise nil.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns ErrInvalid's message followed by the field errors.
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrInvalid.Error())
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.Error())
+	}
+	return b.String()
+}
+
+// Is is a hook for errors.Is. Reports whether target is ErrInvalid or one of its categories.
+func (e *ValidationError) Is(target error) bool {
+	return errors.Is(ErrInvalid, target)
+}
+
+// Unwrap is a hook for errors.Is and errors.As. Returns the field errors.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// MarshalJSON renders the field errors as an object of path to message.
+// Messages for the same path are joined with "; ".
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]string, len(e.Fields))
+	for _, f := range e.Fields {
+		if msg, ok := fields[f.Path]; ok {
+			fields[f.Path] = msg + "; " + f.Err.Error()
+		} else {
+			fields[f.Path] = f.Err.Error()
+		}
+	}
+	return json.Marshal(fields)
+}
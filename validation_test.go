@@ -0,0 +1,53 @@
+package knownerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	errRequired := New("some required error")
+	errTooShort := New("some too short error")
+
+	var v ValidationError
+	v.Add("name", errRequired)
+	v.Add("password", errTooShort.WithOp("some.Op"))
+	v.Add("email", nil)
+	err := v.Err()
+
+	require.Error(t, err)
+	require.Equal(t, "invalid input: name: some required error; password: some too short error", err.Error())
+	require.True(t, errors.Is(err, ErrInvalid))
+	require.True(t, errors.Is(err, errRequired))
+	require.True(t, errors.Is(err, errTooShort))
+
+	var field FieldError
+	require.True(t, errors.As(fmt.Errorf("some context: %w", err), &field))
+	require.Equal(t, "name", field.Path)
+}
+
+func TestValidationError__empty(t *testing.T) {
+	t.Parallel()
+
+	var v ValidationError
+	require.NoError(t, v.Err())
+}
+
+func TestValidationError_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var v ValidationError
+	v.Add("name", New("some required error"))
+	v.Add("password", New("some too short error"))
+	v.Add("password", New("some weak error"))
+
+	data, err := json.Marshal(v.Err())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"some required error","password":"some too short error; some weak error"}`, string(data))
+}